@@ -0,0 +1,111 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/service"
+	"effectiveMobile/internal/songfile"
+	"effectiveMobile/internal/store"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+const exportUsage = "usage: export [-format csv|ndjson|json|m3u|m3u8|xspf] [-sort fields] [filters] file (- for stdout, .gz to compress)"
+
+// runExport writes the songs matching the filters to a file, like GET
+// /api/songs/export does. The filter flags are named after the getsongs
+// query params. A failed export removes the file.
+func runExport(ctx context.Context, cfg config.Config, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "", "file format; by the file extension, csv for stdout, by default")
+	sort := flags.String("sort", "", "comma separated fields, - prefix for descending, as in getsongs")
+	var params entities.SongFilterParams
+	flags.StringVar(&params.GroupName, "groupName", "", "group name")
+	flags.StringVar(&params.Song, "song", "", "song name")
+	flags.StringVar(&params.ArtistID, "artistId", "", "artist id")
+	flags.StringVar(&params.ReleaseDate, "releaseDate", "", "exact release date or year")
+	flags.StringVar(&params.ReleasedFrom, "releasedFrom", "", "released on or after")
+	flags.StringVar(&params.ReleasedTo, "releasedTo", "", "released on or before")
+	flags.StringVar(&params.Year, "year", "", "release year")
+	flags.StringVar(&params.Decade, "decade", "", "release decade, e.g. 1990s")
+	flags.StringVar(&params.Text, "text", "", "part of the text")
+	flags.StringVar(&params.Link, "link", "", "part of the link")
+	flags.StringVar(&params.Filter, "filter", "", "filter expression, as in getsongs")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(exportUsage)
+	}
+	if flags.NArg() != 1 {
+		return errors.New(exportUsage)
+	}
+	path := flags.Arg(0)
+	compress := strings.HasSuffix(strings.ToLower(path), ".gz")
+	if *format == "" {
+		name := strings.ToLower(path)
+		if compress {
+			name = strings.TrimSuffix(name, ".gz")
+		}
+		switch ext := strings.TrimPrefix(filepath.Ext(name), "."); ext {
+		case "":
+			*format = songfile.FormatCSV
+		case "jsonl":
+			*format = songfile.FormatNDJSON
+		default:
+			*format = ext
+		}
+	}
+
+	db, err := store.InitDB(ctx, cfg.DbConnectionString, cfg.MigrateOnStart)
+	if err != nil {
+		return err
+	}
+	defer store.ShutdownDb(ctx, db)
+	storeLevel := store.NewStore(db)
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	// exports do not enrich, so there is no enrichment chain
+	songs := service.NewSongService(ctx, storeLevel.Songs, storeLevel.Jobs, nil, cfg)
+	export, err := songs.ExportSongs(ctx, params, *sort, *format)
+	if errors.Is(err, projectError.ErrIncorrectRequest) {
+		return fmt.Errorf("%w: check the format %q, the sort and the filters", err, *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if path == "-" {
+		return export.Write(os.Stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = file
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(file)
+		w = gz
+	}
+	err = export.Write(w)
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"effectiveMobile/internal/client"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/enrichment"
+	"effectiveMobile/internal/service"
+	"effectiveMobile/internal/songfile"
+	"effectiveMobile/internal/store"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+)
+
+const importUsage = "usage: import [-format csv|ndjson] file (- for stdin)"
+
+// runImport imports a song file like POST /api/songs/import does, but in
+// the foreground, and prints the report. Interrupting it leaves the import
+// failed; running it again finishes it.
+func runImport(ctx context.Context, cfg config.Config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "file format, csv or ndjson; by the file extension or content by default")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(importUsage)
+	}
+	if flags.NArg() != 1 {
+		return errors.New(importUsage)
+	}
+	path := flags.Arg(0)
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			*format = songfile.FormatCSV
+		case ".ndjson", ".jsonl":
+			*format = songfile.FormatNDJSON
+		}
+	}
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	db, err := store.InitDB(ctx, cfg.DbConnectionString, cfg.MigrateOnStart)
+	if err != nil {
+		return err
+	}
+	defer store.ShutdownDb(ctx, db)
+	songInfo, err := client.NewSongInfoClient(cfg)
+	if err != nil {
+		return err
+	}
+	enricher, err := enrichment.NewChainFromConfig(cfg, songInfo)
+	if err != nil {
+		return err
+	}
+	storeLevel := store.NewStore(db)
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	imports := service.NewImportService(ctx, storeLevel.Imports, storeLevel.Songs, enricher, cfg)
+	songImport, err := imports.RunImport(ctx, *format, input)
+	if songImport.ID == 0 {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tSTATUS\tSONG ID\tGROUP\tSONG\tERROR")
+	for _, row := range songImport.Rows {
+		songId := ""
+		if row.SongID != nil {
+			songId = fmt.Sprint(*row.SongID)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", row.Line, row.Status, songId, row.GroupName, row.Song, row.Error)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("import %d %s: %d rows, %d inserted, %d duplicates, %d enrichment failed, %d invalid\n",
+		songImport.ID, songImport.Status, songImport.Total, songImport.Inserted, songImport.Duplicates,
+		songImport.EnrichmentFailed, songImport.Invalid)
+	return err
+}
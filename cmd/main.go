@@ -2,15 +2,21 @@ package main
 
 import (
 	"context"
+	"effectiveMobile/internal/client"
 	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/enrichment"
+	projectError "effectiveMobile/internal/errors"
 	"effectiveMobile/internal/handler"
 	"effectiveMobile/internal/logger"
 	"effectiveMobile/internal/server"
 	"effectiveMobile/internal/service"
 	"effectiveMobile/internal/store"
+	"errors"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -28,21 +34,76 @@ func main() {
 		log.Errorw("error with creating config", zap.Error(err))
 		return
 	}
-	db, err := store.InitDB(ctx, cfg.DbConnectionString)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, cfg, os.Args[2:]); err != nil {
+			log.Errorw("error with running migrations", zap.Error(err))
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(ctx, cfg, os.Args[2:]); err != nil {
+			log.Errorw("error with exporting songs", zap.Error(err))
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(ctx, cfg, os.Args[2:]); err != nil {
+			log.Errorw("error with importing songs", zap.Error(err))
+			os.Exit(1)
+		}
+		return
+	}
+	db, err := store.InitDB(ctx, cfg.DbConnectionString, cfg.MigrateOnStart)
 	if err != nil {
 		log.Errorw("error with creating db", zap.Error(err))
 		return
 	}
 	storeLevel := store.NewStore(db)
 	log.Debug("created store level")
-	service := service.NewService(&storeLevel, cfg.ApiUrl, cfg.ApiTimeout)
+	outboundCtx, cancelOutbound := context.WithCancel(ctx)
+	defer cancelOutbound()
+	songInfo, err := client.NewSongInfoClient(cfg)
+	if err != nil {
+		log.Errorw("error with creating song api client", zap.Error(err))
+		return
+	}
+	enricher, err := enrichment.NewChainFromConfig(cfg, songInfo)
+	if err != nil {
+		log.Errorw("error with creating enrichers", zap.Error(err))
+		return
+	}
+	worker := service.NewEnrichmentWorker(storeLevel.Jobs, enricher, cfg)
+	scheduler := service.NewRefreshScheduler(storeLevel.Songs, enricher, cfg)
+	service := service.NewService(outboundCtx, &storeLevel, enricher, cfg)
 	log.Debug("created service level")
-	handl := handler.NewHandler(service)
+	handl := handler.NewHandler(service, cfg)
 	log.Debug("created handler level")
 	r := handl.InitRoutes(ctx)
+	var background sync.WaitGroup
+	if cfg.EnrichmentMode == config.EnrichmentModeAsync {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			worker.Run(outboundCtx)
+		}()
+	}
+	if cfg.RefreshInterval > 0 {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			scheduler.Run(outboundCtx)
+		}()
+	}
+	srv := server.NewServer(r, cfg.Port, time.Duration(cfg.DrainTimeout)*time.Second)
+	srv.RegisterOnDrainTimeout(func() {
+		log.Infow("drain timeout is exceeded, cancelling outbound requests")
+		cancelOutbound()
+	})
 	go func() {
 		log.Debugw("server is running", "port", cfg.Port)
-		if err := server.NewServer(r, cfg.Port); err != nil {
+		if err := srv.Run(); err != nil {
 			log.Errorw("error with starting server", zap.Error(err))
 			return
 		}
@@ -51,10 +112,24 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
+
+	log.Debugw("server is shutting down")
+	shutdownErr := srv.Shutdown(ctx)
+	if shutdownErr != nil {
+		log.Errorw("error with shutting down server", zap.Error(shutdownErr))
+	}
+	cancelOutbound()
+	background.Wait()
+	service.Wait()
+	if errors.Is(shutdownErr, projectError.ErrHandlersAbandoned) {
+		// abandoned handlers may still use the database
+		log.Errorw("database is left open for abandoned handlers")
+		os.Exit(1)
+	}
 	if err := store.ShutdownDb(ctx, db); err != nil {
 		log.Errorw("error with shutting down service", zap.Error(err))
 		os.Exit(1)
 	}
 
-	log.Debugw("server is shutting down")
+	log.Debugw("server is stopped")
 }
@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/deletesong/{id}": {
-            "delete": {
-                "description": "delete song",
+        "/api/albums": {
+            "get": {
+                "description": "list albums with track counts",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,14 +25,133 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "DeleteSong",
-                "operationId": "delete song",
+                "summary": "GetAlbums",
+                "operationId": "get albums",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "songId",
+                        "description": "artistId",
+                        "name": "artistId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "part of the title, case-insensitive",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.AlbumsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create album with its tracklist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "InsertAlbum",
+                "operationId": "insert album",
+                "parameters": [
+                    {
+                        "description": "Album",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.AlbumRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/albums/{id}": {
+            "get": {
+                "description": "get album with its tracklist ordered by disc and track number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "GetAlbum",
+                "operationId": "get album",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "albumId",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -42,7 +161,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.DeleteResponse"
+                            "$ref": "#/definitions/entities.Album"
                         }
                     },
                     "400": {
@@ -66,9 +185,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/getsongs": {
+        "/api/artists": {
             "get": {
-                "description": "get songs",
+                "description": "list artists with song counts",
                 "consumes": [
                     "application/json"
                 ],
@@ -76,11 +195,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "artists"
                 ],
-                "summary": "GetSongs",
-                "operationId": "get songs",
+                "summary": "GetArtists",
+                "operationId": "get artists",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "part of the name, case-insensitive",
+                        "name": "name",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "limit",
@@ -92,46 +217,58 @@ const docTemplate = `{
                         "description": "page",
                         "name": "page",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ArtistsPage"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "groupName",
-                        "name": "groupName",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "song",
-                        "name": "song",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "releaseDate",
-                        "name": "releaseDate",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "text",
-                        "name": "text",
-                        "in": "query"
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create artist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "InsertArtist",
+                "operationId": "insert artist",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "link",
-                        "name": "link",
-                        "in": "query"
+                        "description": "Artist",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.ArtistRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entities.Song"
-                            }
+                            "$ref": "#/definitions/entities.Artist"
                         }
                     },
                     "400": {
@@ -140,8 +277,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorMessage"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorMessage"
                         }
@@ -155,9 +292,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/gettext/{id}": {
+        "/api/artists/{id}": {
             "get": {
-                "description": "get text song",
+                "description": "get artist with song count",
                 "consumes": [
                     "application/json"
                 ],
@@ -165,42 +302,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "artists"
                 ],
-                "summary": "GetTextSong",
-                "operationId": "get text song",
+                "summary": "GetArtist",
+                "operationId": "get artist",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "songId",
+                        "description": "artistId",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "lineInVerse",
-                        "name": "lineInVerse",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.TextResponse"
+                            "$ref": "#/definitions/entities.Artist"
                         }
                     },
                     "400": {
@@ -222,11 +341,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/insertsong": {
-            "post": {
-                "description": "insert song",
+            },
+            "delete": {
+                "description": "delete artist without songs and albums",
                 "consumes": [
                     "application/json"
                 ],
@@ -234,26 +351,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "artists"
                 ],
-                "summary": "InsertSong",
-                "operationId": "insert song",
+                "summary": "DeleteArtist",
+                "operationId": "delete artist",
                 "parameters": [
                     {
-                        "description": "Song",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entities.SongRequest"
-                        }
+                        "type": "integer",
+                        "description": "artistId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.InsertResponse"
+                            "$ref": "#/definitions/entities.DeleteResponse"
                         }
                     },
                     "400": {
@@ -262,6 +377,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorMessage"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -269,11 +396,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/updatesong/{id}": {
+            },
             "patch": {
-                "description": "update song",
+                "description": "rename artist, its songs get the new group name; 409 if an artist has the name or a song would get the name of another song",
                 "consumes": [
                     "application/json"
                 ],
@@ -281,25 +406,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "artists"
                 ],
-                "summary": "UpdateSong",
-                "operationId": "update song",
+                "summary": "RenameArtist",
+                "operationId": "rename artist",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "songId",
+                        "description": "artistId",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Song",
+                        "description": "Artist",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entities.SongUpdate"
+                            "$ref": "#/definitions/entities.ArtistRequest"
                         }
                     }
                 ],
@@ -307,7 +432,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.TextResponse"
+                            "$ref": "#/definitions/entities.Artist"
                         }
                     },
                     "400": {
@@ -322,6 +447,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorMessage"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -330,28 +461,2781 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entities.DeleteResponse": {
+        },
+        "/api/deletesong/{id}": {
+            "delete": {
+                "description": "delete song",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "DeleteSong",
+                "operationId": "delete song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "songId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getsongs": {
+            "get": {
+                "description": "get songs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "GetSongs",
+                "operationId": "get songs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "groupName",
+                        "name": "groupName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "artistId",
+                        "name": "artistId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "song",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exact release date: DD.MM.YYYY, YYYY-MM-DD, or YYYY for the whole year",
+                        "name": "releaseDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "released on or after: DD.MM.YYYY, YYYY-MM-DD or YYYY",
+                        "name": "releasedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "released on or before: DD.MM.YYYY, YYYY-MM-DD, or YYYY for the end of the year",
+                        "name": "releasedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "release year",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "release decade, e.g. 1990 or 1990s",
+                        "name": "decade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "text",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "link",
+                        "name": "link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields, - prefix for descending, e.g. releaseDate,-group,song; fields: group, song, releaseDate, id",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter expression, e.g. group eq \\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "page",
+                            "cursor"
+                        ],
+                        "type": "string",
+                        "description": "page (default) or cursor",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor or prevCursor of a previous page, implies mode=cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "estimate",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "how to compute total: exact (default in page mode), estimate or none (default in cursor mode)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "envelope",
+                            "array"
+                        ],
+                        "type": "string",
+                        "description": "array returns the legacy bare array of songs",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "format=array returns []entities.Song",
+                        "schema": {
+                            "$ref": "#/definitions/entities.SongsPage"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the first, prev, next and last pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/gettext/{id}": {
+            "get": {
+                "description": "get text song",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "GetTextSong",
+                "operationId": "get text song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "songId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "lineInVerse",
+                        "name": "lineInVerse",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "lines",
+                            "stanza"
+                        ],
+                        "type": "string",
+                        "description": "lines (default) or stanza",
+                        "name": "mode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "mode=lines; mode=stanza returns entities.VersesResponse",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TextResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/insertsong": {
+            "post": {
+                "description": "insert song",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "InsertSong",
+                "operationId": "insert song",
+                "parameters": [
+                    {
+                        "description": "Song",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.SongRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.InsertResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entities.InsertResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.SongExistsMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/jobs/{id}": {
+            "get": {
+                "description": "get enrichment job status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "GetJob",
+                "operationId": "get job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "jobId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.EnrichmentJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/jobs/{id}/retry": {
+            "post": {
+                "description": "requeue a dead enrichment job",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "RetryJob",
+                "operationId": "retry job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "jobId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.RetryJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playlists": {
+            "get": {
+                "description": "list playlists without entries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "GetPlaylists",
+                "operationId": "get playlists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlaylistsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create playlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "InsertPlaylist",
+                "operationId": "insert playlist",
+                "parameters": [
+                    {
+                        "description": "Playlist",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playlists/import": {
+            "post": {
+                "description": "create a playlist from an m3u, m3u8 or xspf file; tracks are matched to songs by group and song name, missing songs are inserted like insertsong does",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "ImportPlaylist",
+                "operationId": "import playlist",
+                "parameters": [
+                    {
+                        "enum": [
+                            "m3u",
+                            "m3u8",
+                            "xspf"
+                        ],
+                        "type": "string",
+                        "description": "playlist format, guessed from the file by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "playlist name, the file title by default",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "description": "playlist file, PLAYLIST_IMPORT_MAX_MB at most",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlaylistImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playlists/{id}": {
+            "get": {
+                "description": "get playlist with its entries in order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "GetPlaylist",
+                "operationId": "get playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "playlistId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete playlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "DeletePlaylist",
+                "operationId": "delete playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "playlistId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "current version of the playlist, 409 if it is not",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "update playlist name or description",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "UpdatePlaylist",
+                "operationId": "update playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "playlistId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Playlist",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlaylistUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playlists/{id}/entries": {
+            "post": {
+                "description": "add song to playlist at position, the entries from there move down; without position the song is appended",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "AddPlaylistEntry",
+                "operationId": "add playlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "playlistId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Entry",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlaylistEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playlists/{id}/entries/{entryId}": {
+            "delete": {
+                "description": "remove entry from playlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "RemovePlaylistEntry",
+                "operationId": "remove playlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "playlistId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "entryId",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "current version of the playlist, 409 if it is not",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playlists/{id}/entries/{entryId}/move": {
+            "post": {
+                "description": "move playlist entry to position",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "MovePlaylistEntry",
+                "operationId": "move playlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "playlistId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "entryId",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Position",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlaylistMoveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playlists/{id}/export": {
+            "get": {
+                "description": "export playlist as a playlist file, song links are the locations; m3u leaves out songs without a link",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "ExportPlaylist",
+                "operationId": "export playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "playlistId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "m3u",
+                            "m3u8",
+                            "xspf"
+                        ],
+                        "type": "string",
+                        "description": "playlist format, m3u8 by default",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "playlist file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playlists/{id}/order": {
+            "put": {
+                "description": "put playlist entries in the given order, entryIds must list every entry once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "ReorderPlaylist",
+                "operationId": "reorder playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "playlistId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlaylistOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/search": {
+            "get": {
+                "description": "full-text search over song titles and lyrics, ranked, with highlighted fragments",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "SearchLyrics",
+                "operationId": "search lyrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search query, websearch syntax: words, \\",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "auto",
+                            "english",
+                            "russian"
+                        ],
+                        "type": "string",
+                        "description": "auto (default), english or russian",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "envelope",
+                            "array"
+                        ],
+                        "type": "string",
+                        "description": "array returns the legacy bare array of results",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "format=array returns []entities.SearchResult",
+                        "schema": {
+                            "$ref": "#/definitions/entities.SearchPage"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the first, prev, next and last pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/search/fuzzy": {
+            "get": {
+                "description": "typo-tolerant, case and accent insensitive search by group and song name, ordered by similarity",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "FuzzySearch",
+                "operationId": "fuzzy search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "group",
+                            "song"
+                        ],
+                        "type": "string",
+                        "description": "all (default), group or song",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "minimal similarity from 0 to 1, FUZZY_THRESHOLD by default",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "envelope",
+                            "array"
+                        ],
+                        "type": "string",
+                        "description": "array returns the legacy bare array of results",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "format=array returns []entities.FuzzyResult",
+                        "schema": {
+                            "$ref": "#/definitions/entities.FuzzyPage"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the first, prev, next and last pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/songs/duplicates": {
+            "get": {
+                "description": "find clusters of songs that are likely one, best first: two songs are linked when their group and song names are trigram-similar, compared like fuzzy search does, and when both have lyrics the lyrics count as much as the names. Songs linked through each other are one cluster. Merge a cluster with mergesongs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "FindDuplicates",
+                "operationId": "find duplicates",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "minimal similarity, 0 to 1, DUPLICATE_THRESHOLD by default",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.DuplicatesPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/songs/export": {
+            "get": {
+                "description": "export the songs matching getsongs filters as a file streamed from a server-side cursor: csv, ndjson, a json array, or a playlist whose locations are song links (m3u leaves out songs without a link). The response is gzipped if Accept-Encoding allows it",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "ExportSongs",
+                "operationId": "export songs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "json",
+                            "m3u",
+                            "m3u8",
+                            "xspf"
+                        ],
+                        "type": "string",
+                        "description": "file format, csv by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "groupName",
+                        "name": "groupName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "artistId",
+                        "name": "artistId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "song",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exact release date: DD.MM.YYYY, YYYY-MM-DD, or YYYY for the whole year",
+                        "name": "releaseDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "released on or after: DD.MM.YYYY, YYYY-MM-DD or YYYY",
+                        "name": "releasedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "released on or before: DD.MM.YYYY, YYYY-MM-DD, or YYYY for the end of the year",
+                        "name": "releasedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "release year",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "release decade, e.g. 1990 or 1990s",
+                        "name": "decade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "text",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "link",
+                        "name": "link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields, - prefix for descending, as in getsongs",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter expression, as in getsongs",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "song file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/songs/import": {
+            "post": {
+                "description": "start a bulk import of a csv or ndjson song file: each row is enriched like insertsong does, unless the file has all its fields, and inserted; songs that exist already are duplicates, so importing a file again changes nothing. Follow the import and its per-row report with getimport",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "ImportSongs",
+                "operationId": "import songs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "file format, guessed from the file by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "song file: csv with group, song and optional releaseDate, text, link, album, albumReleaseDate, albumCover, disc, track columns, or one song json per line, IMPORT_MAX_MB at most",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entities.SongImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/songs/imports/{id}": {
+            "get": {
+                "description": "get a song import: its status, counters and a page of its per-row report in file order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "GetImport",
+                "operationId": "get import",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "importId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "inserted",
+                            "duplicate",
+                            "enrichment_failed",
+                            "invalid"
+                        ],
+                        "type": "string",
+                        "description": "only rows of the status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.SongImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/songs/{id}/lrc": {
+            "get": {
+                "description": "export synced lyrics in LRC format",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "lyrics"
+                ],
+                "summary": "ExportLRC",
+                "operationId": "export lrc",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "songId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "LRC file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "replace song text with synced lyrics in LRC format",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lyrics"
+                ],
+                "summary": "ImportLRC",
+                "operationId": "import lrc",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "songId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "LRC file",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.UpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/songs/{id}/lyrics/at": {
+            "get": {
+                "description": "get the synced lyrics line active at a playback position",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lyrics"
+                ],
+                "summary": "GetActiveLine",
+                "operationId": "get active line",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "songId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "seconds (83.5) or mm:ss.xx (01:23.50)",
+                        "name": "position",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ActiveLineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/songs/{id}/merge": {
+            "post": {
+                "description": "merge songs into the song of the path, which is kept as it is: the playlist entries, album tracks and import rows of the merged songs move over to it, and they are deleted with their lyrics and enrichment jobs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "MergeSongs",
+                "operationId": "merge songs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "songId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Songs to merge",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.MergeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/songs/{id}/refresh": {
+            "post": {
+                "description": "re-query song info and merge it, keeping fields edited by hand",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "RefreshSong",
+                "operationId": "refresh song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "songId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/songs/{id}/sections": {
+            "get": {
+                "description": "get lyrics split into verse/chorus/bridge/intro/outro sections",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lyrics"
+                ],
+                "summary": "GetSections",
+                "operationId": "get sections",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "songId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "verse",
+                            "chorus",
+                            "bridge",
+                            "intro",
+                            "outro"
+                        ],
+                        "type": "string",
+                        "description": "section kind",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "section number within its kind",
+                        "name": "number",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.SectionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/updatesong/{id}": {
+            "patch": {
+                "description": "update song",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "UpdateSong",
+                "operationId": "update song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "songId",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Song",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.SongUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TextResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.SongExistsMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entities.ActiveLineResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "nextTime": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.Album": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "artistId": {
+                    "type": "integer"
+                },
+                "coverLink": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "trackCount": {
+                    "type": "integer"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.AlbumTrack"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.AlbumRequest": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "coverLink": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.AlbumTrackRequest"
+                    }
+                }
+            }
+        },
+        "entities.AlbumTrack": {
+            "type": "object",
+            "properties": {
+                "discNumber": {
+                    "type": "integer"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "trackNumber": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.AlbumTrackRequest": {
+            "type": "object",
+            "properties": {
+                "discNumber": {
+                    "type": "integer"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "trackNumber": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.AlbumsPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Album"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.Artist": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "songCount": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.ArtistRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.ArtistsPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Artist"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.DeleteResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "entities.DuplicateCluster": {
+            "type": "object",
+            "properties": {
+                "pairs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.DuplicatePair"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                },
+                "songs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Song"
+                    }
+                }
+            }
+        },
+        "entities.DuplicatePair": {
+            "type": "object",
+            "properties": {
+                "nameScore": {
+                    "type": "number"
+                },
+                "otherId": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "textScore": {
+                    "type": "number"
+                }
+            }
+        },
+        "entities.DuplicatesPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.DuplicateCluster"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.EnrichmentJob": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "maxAttempts": {
+                    "type": "integer"
+                },
+                "runAt": {
+                    "type": "string"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.FieldSources": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "entities.FuzzyPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.FuzzyResult"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "prev": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.FuzzyResult": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "description": "Album is the album enrichment found the song on. It is stored in\nalbums and album_tracks, not in the song row.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.SongAlbum"
+                        }
+                    ]
+                },
+                "artistId": {
+                    "description": "ArtistID references the artist; GroupName is a cached copy of its name.",
+                    "type": "integer"
+                },
+                "enrichedAt": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "releaseDateRaw": {
+                    "description": "ReleaseDateRaw is the text of a release date migration 0010 could not\nparse; it is cleared once the song gets a release date.",
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "sources": {
+                    "$ref": "#/definitions/entities.FieldSources"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.ImportedTrack": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.InsertResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "jobId": {
+                    "type": "integer"
+                },
+                "sources": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.LyricsSection": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.MergeRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "entities.Playlist": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.PlaylistEntry"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "songCount": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.PlaylistEntry": {
+            "type": "object",
+            "properties": {
+                "addedAt": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "songId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.PlaylistEntryRequest": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.PlaylistImportResponse": {
+            "type": "object",
+            "properties": {
+                "playlist": {
+                    "$ref": "#/definitions/entities.Playlist"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.ImportedTrack"
+                    }
+                }
+            }
+        },
+        "entities.PlaylistMoveRequest": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.PlaylistOrderRequest": {
+            "type": "object",
+            "properties": {
+                "entryIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.PlaylistRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.PlaylistUpdate": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.PlaylistsPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Playlist"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.RetryJobResponse": {
             "type": "object",
             "properties": {
+                "id": {
+                    "type": "string"
+                },
                 "status": {
-                    "type": "boolean"
+                    "type": "string"
                 }
             }
         },
-        "entities.InsertResponse": {
+        "entities.SearchPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.SearchResult"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "prev": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.SearchResult": {
             "type": "object",
             "properties": {
+                "album": {
+                    "description": "Album is the album enrichment found the song on. It is stored in\nalbums and album_tracks, not in the song row.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.SongAlbum"
+                        }
+                    ]
+                },
+                "artistId": {
+                    "description": "ArtistID references the artist; GroupName is a cached copy of its name.",
+                    "type": "integer"
+                },
+                "enrichedAt": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "headline": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "releaseDateRaw": {
+                    "description": "ReleaseDateRaw is the text of a release date migration 0010 could not\nparse; it is cleared once the song gets a release date.",
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "sources": {
+                    "$ref": "#/definitions/entities.FieldSources"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.SectionsResponse": {
+            "type": "object",
+            "properties": {
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.LyricsSection"
+                    }
                 }
             }
         },
         "entities.Song": {
             "type": "object",
             "properties": {
+                "album": {
+                    "description": "Album is the album enrichment found the song on. It is stored in\nalbums and album_tracks, not in the song row.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.SongAlbum"
+                        }
+                    ]
+                },
+                "artistId": {
+                    "description": "ArtistID references the artist; GroupName is a cached copy of its name.",
+                    "type": "integer"
+                },
+                "enrichedAt": {
+                    "type": "string"
+                },
                 "group": {
                     "type": "string"
                 },
@@ -362,16 +3246,118 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "releaseDateRaw": {
+                    "description": "ReleaseDateRaw is the text of a release date migration 0010 could not\nparse; it is cleared once the song gets a release date.",
                     "type": "string"
                 },
                 "song": {
                     "type": "string"
                 },
+                "sources": {
+                    "$ref": "#/definitions/entities.FieldSources"
+                },
+                "status": {
+                    "type": "string"
+                },
                 "text": {
                     "type": "string"
                 }
             }
         },
+        "entities.SongAlbum": {
+            "type": "object",
+            "properties": {
+                "coverLink": {
+                    "type": "string"
+                },
+                "discNumber": {
+                    "type": "integer"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "trackNumber": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.SongImport": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "enrichmentFailed": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inserted": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.SongImportRow"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.SongImportRow": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "entities.SongRequest": {
             "type": "object",
             "properties": {
@@ -393,7 +3379,8 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "releaseDate": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "16.07.2006"
                 },
                 "song": {
                     "type": "string"
@@ -403,6 +3390,41 @@ const docTemplate = `{
                 }
             }
         },
+        "entities.SongsPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Song"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "prev": {
+                    "type": "string"
+                },
+                "prevCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalEstimated": {
+                    "type": "boolean"
+                }
+            }
+        },
         "entities.TextResponse": {
             "type": "object",
             "properties": {
@@ -411,6 +3433,14 @@ const docTemplate = `{
                 }
             }
         },
+        "entities.UpdateResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
         "errors.ErrorMessage": {
             "type": "object",
             "properties": {
@@ -418,6 +3448,17 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "errors.SongExistsMessage": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
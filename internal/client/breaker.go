@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"effectiveMobile/internal/logger"
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case stateOpen:
+		return "open"
+	case stateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// breaker is a consecutive-failures circuit breaker. After threshold failures
+// in a row it opens and rejects calls for cooldown, then lets a single probe
+// through; the probe's outcome closes or re-opens it.
+type breaker struct {
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	threshold int
+	cooldown  time.Duration
+	metrics   *metrics
+}
+
+func newBreaker(threshold int, cooldown time.Duration, metrics *metrics) *breaker {
+	b := &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		metrics:   metrics,
+	}
+	metrics.setState(stateClosed)
+	return b
+}
+
+// allow reports whether a call may be made now.
+func (b *breaker) allow(ctx context.Context) bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case stateOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.transition(ctx, stateHalfOpen)
+		b.probing = true
+		return true
+	case stateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *breaker) success(ctx context.Context) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+	if b.state != stateClosed {
+		b.transition(ctx, stateClosed)
+	}
+}
+
+func (b *breaker) failure(ctx context.Context) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.state == stateHalfOpen || (b.state == stateClosed && b.failures >= b.threshold) {
+		b.openedAt = time.Now()
+		b.transition(ctx, stateOpen)
+	}
+}
+
+// release frees a half-open probe slot without judging the upstream, e.g.
+// when the caller gave up before the probe finished.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *breaker) transition(ctx context.Context, to breakerState) {
+	log := logger.LoggerFromContext(ctx)
+	log.Warnw("song api circuit breaker changed state", "from", b.state.String(), "to", to.String(), "failures", b.failures)
+	b.state = to
+	b.metrics.setState(to)
+	if to == stateOpen {
+		b.metrics.breakerOpened.Add(1)
+	}
+}
@@ -0,0 +1,314 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SongInfoClient calls the external song api /info endpoint. Failed attempts
+// (transport errors, timeouts, 5xx and 429) are retried with jittered
+// exponential backoff within a total time budget, and a circuit breaker
+// fails calls fast while the upstream keeps failing.
+type SongInfoClient struct {
+	baseUrl     *url.URL
+	headers     http.Header
+	httpClient  http.Client
+	retries     int
+	retryBudget time.Duration
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	breaker     *breaker
+	metrics     *metrics
+}
+
+func NewSongInfoClient(cfg config.Config) (*SongInfoClient, error) {
+	baseUrl, err := parseBaseUrl(cfg.ApiUrl)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	headers := make(http.Header)
+	if cfg.ApiBearerToken != "" {
+		headers.Set("Authorization", "Bearer "+cfg.ApiBearerToken)
+	}
+	if cfg.ApiKey != "" {
+		headers.Set(cfg.ApiKeyHeader, cfg.ApiKey)
+	}
+
+	return &SongInfoClient{
+		baseUrl: baseUrl,
+		headers: headers,
+		httpClient: http.Client{
+			Timeout:   time.Duration(cfg.ApiTimeout) * time.Second,
+			Transport: transport,
+		},
+		retries:     cfg.ApiRetries,
+		retryBudget: time.Duration(cfg.ApiRetryBudget) * time.Second,
+		backoffBase: time.Duration(cfg.ApiBackoffBase) * time.Millisecond,
+		backoffMax:  time.Duration(cfg.ApiBackoffMax) * time.Millisecond,
+		breaker:     newBreaker(cfg.BreakerThreshold, time.Duration(cfg.BreakerCooldown)*time.Second, songApiMetrics),
+		metrics:     songApiMetrics,
+	}, nil
+}
+
+// parseBaseUrl accepts a full base url with scheme and optional path prefix,
+// e.g. https://example.com/v1. A bare host:port is treated as http for
+// compatibility with older configs.
+func parseBaseUrl(apiUrl string) (*url.URL, error) {
+	if !strings.Contains(apiUrl, "://") {
+		apiUrl = "http://" + apiUrl
+	}
+	baseUrl, err := url.Parse(apiUrl)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect API_URL: %w", err)
+	}
+	if baseUrl.Scheme != "http" && baseUrl.Scheme != "https" {
+		return nil, fmt.Errorf("incorrect API_URL: unsupported scheme %q", baseUrl.Scheme)
+	}
+	if baseUrl.Host == "" {
+		return nil, fmt.Errorf("incorrect API_URL: host is empty")
+	}
+	return baseUrl, nil
+}
+
+func newTLSConfig(cfg config.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.ApiCaFile != "" {
+		caCert, err := os.ReadFile(cfg.ApiCaFile)
+		if err != nil {
+			return nil, fmt.Errorf("error with reading API_CA_FILE: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("API_CA_FILE contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.ApiCertFile != "" || cfg.ApiKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ApiCertFile, cfg.ApiKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error with loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func (c *SongInfoClient) infoUrl(group, song string) string {
+	requestUrl := c.baseUrl.JoinPath("info")
+	query := requestUrl.Query()
+	query.Set("group", group)
+	query.Set("song", song)
+	requestUrl.RawQuery = query.Encode()
+	return requestUrl.String()
+}
+
+// attemptError describes a failed attempt and whether it is worth retrying.
+type attemptError struct {
+	err        error
+	retryable  bool
+	retryAfter time.Duration
+}
+
+func (c *SongInfoClient) GetInfo(ctx context.Context, group, song string) (entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	if c.retryBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.retryBudget)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		if !c.breaker.allow(ctx) {
+			c.metrics.rejected.Add(1)
+			log.Warnw("song api circuit breaker is open, request is rejected")
+			return entities.Song{}, errors.ErrSongApiUnavailable
+		}
+
+		c.metrics.requests.Add(1)
+		songDetail, attemptErr := c.do(ctx, group, song)
+		if attemptErr == nil {
+			c.breaker.success(ctx)
+			return songDetail, nil
+		}
+
+		if !attemptErr.retryable {
+			if ctx.Err() == nil {
+				c.breaker.success(ctx)
+			} else {
+				c.breaker.release()
+			}
+			return entities.Song{}, attemptErr.err
+		}
+
+		c.metrics.failures.Add(1)
+		c.breaker.failure(ctx)
+		if attempt >= c.retries {
+			log.Errorw("song api retries are exhausted", "attempts", attempt+1, zap.Error(attemptErr.err))
+			return entities.Song{}, attemptErr.err
+		}
+
+		wait := c.backoff(attempt)
+		if attemptErr.retryAfter > 0 {
+			wait = attemptErr.retryAfter
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			log.Errorw("song api retry budget is exhausted", "attempts", attempt+1, zap.Error(attemptErr.err))
+			return entities.Song{}, attemptErr.err
+		}
+
+		log.Warnw("retrying song api request", "attempt", attempt+1, "wait", wait, zap.Error(attemptErr.err))
+		c.metrics.retries.Add(1)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return entities.Song{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *SongInfoClient) do(ctx context.Context, group, song string) (entities.Song, *attemptError) {
+	log := logger.LoggerFromContext(ctx)
+	requestUrl := c.infoUrl(group, song)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl, nil)
+	if err != nil {
+		log.Errorw("error with creating request", zap.Error(err))
+		return entities.Song{}, &attemptError{err: err}
+	}
+	for name, values := range c.headers {
+		request.Header[name] = values
+	}
+	log.Infow("doing request to song api", "requestUrl", requestUrl)
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		log.Errorw("error with sending request", zap.Error(err))
+		// a cancelled caller is not the upstream's fault
+		return entities.Song{}, &attemptError{err: err, retryable: ctx.Err() == nil}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests {
+		log.Errorw("song api responded with error", "status", response.StatusCode)
+		return entities.Song{}, &attemptError{
+			err:        fmt.Errorf("%w: status %d", errors.ErrAnotherStatucCode, response.StatusCode),
+			retryable:  true,
+			retryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+		}
+	}
+	if response.StatusCode != http.StatusOK {
+		log.Errorw("song api responded with unexpected status", "status", response.StatusCode)
+		return entities.Song{}, &attemptError{
+			err: fmt.Errorf("%w: status %d", errors.ErrAnotherStatucCode, response.StatusCode),
+		}
+	}
+
+	var songDetail infoResponse
+	if err := json.NewDecoder(response.Body).Decode(&songDetail); err != nil {
+		log.Errorw("error with decoding response", zap.Error(err))
+		return entities.Song{}, &attemptError{err: err}
+	}
+	// a malformed date should not cost the song its text and link
+	releaseDate, err := entities.ParseDate(songDetail.ReleaseDate)
+	if err != nil {
+		log.Warnw("song api returned incorrect release date", zap.Error(err))
+	}
+	return entities.Song{
+		ReleaseDate: releaseDate,
+		Text:        songDetail.Text,
+		Link:        songDetail.Link,
+		Album:       songDetail.Album.song(log),
+	}, nil
+}
+
+// infoResponse is the /info response body.
+type infoResponse struct {
+	ReleaseDate string         `json:"releaseDate"`
+	Text        string         `json:"text"`
+	Link        string         `json:"link"`
+	Album       *albumResponse `json:"album"`
+}
+
+// albumResponse is the optional album of the /info response.
+type albumResponse struct {
+	Title       string `json:"title"`
+	ReleaseDate string `json:"releaseDate"`
+	CoverLink   string `json:"coverLink"`
+	DiscNumber  int    `json:"discNumber"`
+	TrackNumber int    `json:"trackNumber"`
+}
+
+func (a *albumResponse) song(log *zap.SugaredLogger) *entities.SongAlbum {
+	if a == nil || a.Title == "" {
+		return nil
+	}
+	releaseDate, err := entities.ParseDate(a.ReleaseDate)
+	if err != nil {
+		log.Warnw("song api returned incorrect album release date", zap.Error(err))
+	}
+	return &entities.SongAlbum{
+		Title:       a.Title,
+		ReleaseDate: releaseDate,
+		CoverLink:   a.CoverLink,
+		DiscNumber:  a.DiscNumber,
+		TrackNumber: a.TrackNumber,
+	}
+}
+
+// backoff returns a full-jitter exponential delay for the given attempt.
+func (c *SongInfoClient) backoff(attempt int) time.Duration {
+	if c.backoffBase <= 0 {
+		return 0
+	}
+	ceiling := c.backoffBase << attempt
+	if ceiling <= 0 || (c.backoffMax > 0 && ceiling > c.backoffMax) {
+		ceiling = c.backoffMax
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+// parseRetryAfter accepts both forms of the header: delay-seconds and HTTP-date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,36 @@
+package client
+
+import "expvar"
+
+// metrics are published through expvar under the "songapi" key.
+type metrics struct {
+	requests      *expvar.Int
+	retries       *expvar.Int
+	failures      *expvar.Int
+	rejected      *expvar.Int
+	breakerOpened *expvar.Int
+	breakerState  *expvar.String
+}
+
+var songApiMetrics = func() *metrics {
+	m := &metrics{
+		requests:      new(expvar.Int),
+		retries:       new(expvar.Int),
+		failures:      new(expvar.Int),
+		rejected:      new(expvar.Int),
+		breakerOpened: new(expvar.Int),
+		breakerState:  new(expvar.String),
+	}
+	vars := expvar.NewMap("songapi")
+	vars.Set("requests", m.requests)
+	vars.Set("retries", m.retries)
+	vars.Set("failures", m.failures)
+	vars.Set("rejected", m.rejected)
+	vars.Set("breaker_opened", m.breakerOpened)
+	vars.Set("breaker_state", m.breakerState)
+	return m
+}()
+
+func (m *metrics) setState(state breakerState) {
+	m.breakerState.Set(state.String())
+}
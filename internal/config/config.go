@@ -4,12 +4,51 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+const (
+	EnrichmentModeSync  = "sync"
+	EnrichmentModeAsync = "async"
+)
+
 type Config struct {
-	Port               string `env:"PORT"`
-	DbConnectionString string `env:"DB_CONNECTION_STRING"`
-	ApiUrl             string `env:"API_URL"`
-	ApiTimeout         int    `env:"API_TIMEOUT"`
-	LogLevel           string `env:"LOG_LEVEL"`
+	Port                   string  `env:"PORT"`
+	DbConnectionString     string  `env:"DB_CONNECTION_STRING"`
+	ApiUrl                 string  `env:"API_URL"`
+	ApiTimeout             int     `env:"API_TIMEOUT"`
+	ApiCaFile              string  `env:"API_CA_FILE"`
+	ApiCertFile            string  `env:"API_CERT_FILE"`
+	ApiKeyFile             string  `env:"API_KEY_FILE"`
+	ApiBearerToken         string  `env:"API_BEARER_TOKEN"`
+	ApiKey                 string  `env:"API_KEY"`
+	ApiKeyHeader           string  `env:"API_KEY_HEADER" env-default:"X-API-Key"`
+	ApiRetries             int     `env:"API_RETRIES" env-default:"3"`
+	ApiRetryBudget         int     `env:"API_RETRY_BUDGET" env-default:"30"`
+	ApiBackoffBase         int     `env:"API_BACKOFF_BASE" env-default:"200"`
+	ApiBackoffMax          int     `env:"API_BACKOFF_MAX" env-default:"5000"`
+	BreakerThreshold       int     `env:"BREAKER_THRESHOLD" env-default:"5"`
+	BreakerCooldown        int     `env:"BREAKER_COOLDOWN" env-default:"30"`
+	Enrichers              string  `env:"ENRICHERS" env-default:"api"`
+	EnrichmentCatalogFile  string  `env:"ENRICHMENT_CATALOG_FILE"`
+	EnrichmentMode         string  `env:"ENRICHMENT_MODE" env-default:"sync"`
+	EnrichmentWorkers      int     `env:"ENRICHMENT_WORKERS" env-default:"4"`
+	EnrichmentMaxAttempts  int     `env:"ENRICHMENT_MAX_ATTEMPTS" env-default:"5"`
+	EnrichmentPollInterval int     `env:"ENRICHMENT_POLL_INTERVAL" env-default:"2"`
+	EnrichmentRetryBase    int     `env:"ENRICHMENT_RETRY_BASE" env-default:"10"`
+	EnrichmentRetryMax     int     `env:"ENRICHMENT_RETRY_MAX" env-default:"600"`
+	EnrichmentLease        int     `env:"ENRICHMENT_LEASE" env-default:"120"`
+	RefreshInterval        int     `env:"REFRESH_INTERVAL" env-default:"0"`
+	RefreshMaxAge          int     `env:"REFRESH_MAX_AGE" env-default:"720"`
+	RefreshBatch           int     `env:"REFRESH_BATCH" env-default:"50"`
+	FuzzyThreshold         float64 `env:"FUZZY_THRESHOLD" env-default:"0.2"`
+	DuplicateThreshold     float64 `env:"DUPLICATE_THRESHOLD" env-default:"0.5"`
+	PageMaxLimit           int     `env:"PAGE_MAX_LIMIT" env-default:"100"`
+	PlaylistImportMax      int     `env:"PLAYLIST_IMPORT_MAX" env-default:"500"`
+	PlaylistImportMaxMB    int     `env:"PLAYLIST_IMPORT_MAX_MB" env-default:"2"`
+	ImportWorkers          int     `env:"IMPORT_WORKERS" env-default:"4"`
+	ImportBatch            int     `env:"IMPORT_BATCH" env-default:"100"`
+	ImportMaxMB            int     `env:"IMPORT_MAX_MB" env-default:"32"`
+	LogLevel               string  `env:"LOG_LEVEL"`
+	DrainTimeout           int     `env:"DRAIN_TIMEOUT" env-default:"15"`
+	MigrateOnStart         bool    `env:"MIGRATE_ON_START" env-default:"false"`
 }
 
 func NewConfig() (Config, error) {
@@ -0,0 +1,130 @@
+package enrichment
+
+import (
+	"context"
+	"effectiveMobile/internal/client"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Enricher is a source of song metadata. It returns only the fields it
+// knows, leaving the rest empty, and ErrSongInfoNotFound if it has nothing
+// for the song.
+type Enricher interface {
+	Name() string
+	Enrich(ctx context.Context, group, song string) (entities.Song, error)
+}
+
+// Field names as they appear in the song json.
+const (
+	FieldReleaseDate = "releaseDate"
+	FieldText        = "text"
+	FieldLink        = "link"
+)
+
+type Result struct {
+	Song entities.Song
+	// Sources maps a field name to the provider that supplied it.
+	Sources map[string]string
+}
+
+// Chain asks its providers in order and takes every field from the first
+// provider that has it. Album info comes from the first provider asked that
+// has it; it does not make the chain ask further.
+type Chain struct {
+	providers []Enricher
+}
+
+func NewChain(providers ...Enricher) *Chain {
+	return &Chain{
+		providers: providers,
+	}
+}
+
+// NewChainFromConfig builds the chain listed in ENRICHERS, e.g. "catalog,api".
+func NewChainFromConfig(cfg config.Config, songInfo *client.SongInfoClient) (*Chain, error) {
+	providers := make([]Enricher, 0)
+	for _, name := range strings.Split(cfg.Enrichers, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "":
+			continue
+		case ProviderApi:
+			providers = append(providers, NewApiEnricher(songInfo))
+		case ProviderCatalog:
+			catalog, err := NewCatalogEnricher(cfg.EnrichmentCatalogFile)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, catalog)
+		case ProviderStub:
+			providers = append(providers, NewStubEnricher(StubSong))
+		default:
+			return nil, fmt.Errorf("unknown enricher %q", name)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no enrichers are configured")
+	}
+	return NewChain(providers...), nil
+}
+
+// Enrich fails only if no provider supplied anything and at least one of
+// them failed; the first such error is returned.
+func (c *Chain) Enrich(ctx context.Context, group, song string) (Result, error) {
+	log := logger.LoggerFromContext(ctx)
+	result := Result{
+		Sources: make(map[string]string),
+	}
+	var firstErr error
+	for _, provider := range c.providers {
+		details, err := provider.Enrich(ctx, group, song)
+		if err != nil {
+			if !stdErrors.Is(err, errors.ErrSongInfoNotFound) {
+				log.Warnw("enricher failed", "provider", provider.Name(), zap.Error(err))
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			continue
+		}
+		if result.Song.ReleaseDate.IsZero() && !details.ReleaseDate.IsZero() {
+			result.Song.ReleaseDate = details.ReleaseDate
+			result.Sources[FieldReleaseDate] = provider.Name()
+		}
+		fill(&result, FieldText, &result.Song.Text, details.Text, provider.Name())
+		fill(&result, FieldLink, &result.Song.Link, details.Link, provider.Name())
+		if result.Song.Album == nil && details.Album != nil && details.Album.Title != "" {
+			result.Song.Album = details.Album
+		}
+		if len(result.Sources) == 3 {
+			break
+		}
+	}
+	if len(result.Sources) == 0 {
+		if firstErr != nil {
+			return result, firstErr
+		}
+		return result, errors.ErrSongInfoNotFound
+	}
+	log.Infow("song is enriched", "sources", result.Sources)
+	return result, nil
+}
+
+func fill(result *Result, field string, dst *string, value, provider string) {
+	if *dst != "" || value == "" {
+		return
+	}
+	*dst = value
+	result.Sources[field] = provider
+}
@@ -0,0 +1,150 @@
+package enrichment
+
+import (
+	"context"
+	"effectiveMobile/internal/client"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/songfile"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderApi     = "api"
+	ProviderCatalog = "catalog"
+	ProviderStub    = "stub"
+)
+
+// ApiEnricher queries the external song api /info endpoint.
+type ApiEnricher struct {
+	songInfo *client.SongInfoClient
+}
+
+func NewApiEnricher(songInfo *client.SongInfoClient) *ApiEnricher {
+	return &ApiEnricher{
+		songInfo: songInfo,
+	}
+}
+
+func (e *ApiEnricher) Name() string {
+	return ProviderApi
+}
+
+func (e *ApiEnricher) Enrich(ctx context.Context, group, song string) (entities.Song, error) {
+	return e.songInfo.GetInfo(ctx, group, song)
+}
+
+// CatalogEnricher looks songs up in a local .json or .csv file loaded at
+// start. The json file is an array of song objects; the csv file has a
+// header row with group, song, releaseDate, text and link columns, and
+// optional album columns, see the songfile package.
+type CatalogEnricher struct {
+	songs map[string]entities.Song
+}
+
+func NewCatalogEnricher(path string) (*CatalogEnricher, error) {
+	if path == "" {
+		return nil, fmt.Errorf("ENRICHMENT_CATALOG_FILE is not set")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error with opening catalog: %w", err)
+	}
+	defer file.Close()
+
+	var songs []entities.Song
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.NewDecoder(file).Decode(&songs); err != nil {
+			return nil, fmt.Errorf("error with decoding catalog: %w", err)
+		}
+	case ".csv":
+		songs, err = readCatalogCSV(file)
+		if err != nil {
+			return nil, fmt.Errorf("error with reading catalog: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported catalog format %q", filepath.Ext(path))
+	}
+
+	catalog := &CatalogEnricher{
+		songs: make(map[string]entities.Song, len(songs)),
+	}
+	for _, song := range songs {
+		catalog.songs[catalogKey(song.GroupName, song.Song)] = song
+	}
+	return catalog, nil
+}
+
+// readCatalogCSV reads a catalog.csv; rows without the group or song name
+// are skipped, any other bad row fails the whole file.
+func readCatalogCSV(r io.Reader) ([]entities.Song, error) {
+	reader, err := songfile.NewReader(r, songfile.FormatCSV)
+	if err != nil {
+		return nil, err
+	}
+	songs := make([]entities.Song, 0)
+	for {
+		song, err := reader.Read()
+		if err == io.EOF {
+			return songs, nil
+		}
+		if stdErrors.Is(err, songfile.ErrNameMissing) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
+}
+
+func catalogKey(group, song string) string {
+	return strings.ToLower(strings.TrimSpace(group)) + "\x00" + strings.ToLower(strings.TrimSpace(song))
+}
+
+func (e *CatalogEnricher) Name() string {
+	return ProviderCatalog
+}
+
+func (e *CatalogEnricher) Enrich(ctx context.Context, group, song string) (entities.Song, error) {
+	details, ok := e.songs[catalogKey(group, song)]
+	if !ok {
+		return entities.Song{}, errors.ErrSongInfoNotFound
+	}
+	return details, nil
+}
+
+// StubSong is what the stub provider returns for every song.
+var StubSong = entities.Song{
+	ReleaseDate: entities.NewDate(2000, time.January, 1),
+	Text:        "Stub verse line\nStub verse line",
+	Link:        "https://example.com/stub",
+}
+
+// StubEnricher returns the same fields for every song. It is meant for tests
+// and local runs without the external api.
+type StubEnricher struct {
+	song entities.Song
+}
+
+func NewStubEnricher(song entities.Song) *StubEnricher {
+	return &StubEnricher{
+		song: song,
+	}
+}
+
+func (e *StubEnricher) Name() string {
+	return ProviderStub
+}
+
+func (e *StubEnricher) Enrich(ctx context.Context, group, song string) (entities.Song, error) {
+	return e.song, nil
+}
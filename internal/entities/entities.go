@@ -1,22 +1,439 @@
 package entities
 
+import (
+	"database/sql/driver"
+	"effectiveMobile/internal/filter"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
 type Song struct {
-	ID          uint   `gorm:"primaryKey" json:"id"`
-	GroupName   string `json:"group"`
-	Song        string `json:"song"`
-	ReleaseDate string `json:"releaseDate"`
-	Text        string `json:"text"`
-	Link        string `json:"link"`
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	GroupName    string       `json:"group"`
+	Song         string       `json:"song"`
+	ReleaseDate  Date         `gorm:"type:date" json:"releaseDate" swaggertype:"string" example:"16.07.2006"`
+	Text         string       `json:"text"`
+	Link         string       `json:"link"`
+	Status       string       `json:"status"`
+	FieldSources FieldSources `gorm:"type:jsonb" json:"sources,omitempty"`
+	EnrichedAt   *time.Time   `json:"enrichedAt,omitempty"`
+	// ArtistID references the artist; GroupName is a cached copy of its name.
+	ArtistID *uint `json:"artistId,omitempty"`
+	// Album is the album enrichment found the song on. It is stored in
+	// albums and album_tracks, not in the song row.
+	Album *SongAlbum `gorm:"-" json:"album,omitempty"`
+	// LyricsOffsetMs is the [offset:] of imported synced lyrics.
+	LyricsOffsetMs int `json:"-"`
+	// GroupKey and SongKey are translit.Key of GroupName and Song, used
+	// for matching across Cyrillic and Latin spellings.
+	GroupKey string `json:"-"`
+	SongKey  string `json:"-"`
+	// ReleaseDateRaw is the text of a release date migration 0010 could not
+	// parse; it is cleared once the song gets a release date.
+	ReleaseDateRaw *string `gorm:"<-:update" json:"releaseDateRaw,omitempty"`
+}
+
+// SourceManual marks a field edited by hand through UpdateSong. Enrichment
+// never overwrites such fields.
+const SourceManual = "manual"
+
+// FieldSources maps a song json field name to where its value came from:
+// an enrichment provider or SourceManual.
+type FieldSources map[string]string
+
+func (f *FieldSources) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported field sources type %T", value)
+	}
+	return json.Unmarshal(data, f)
+}
+
+func (f FieldSources) Value() (driver.Value, error) {
+	if f == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Date is a calendar date without time, stored as DATE. It is written in
+// json as DD.MM.YYYY and read from DD.MM.YYYY, ISO 8601 (a date or a date
+// with time) or a year alone, meaning 1 January. The zero Date is an
+// unknown date: "" in json and NULL in the database.
+type Date struct {
+	time.Time
+}
+
+// DateLayout is the json and display layout of Date.
+const DateLayout = "02.01.2006"
+
+// dateLayouts are the accepted input layouts, tried in order.
+var dateLayouts = []string{DateLayout, "2006-01-02", time.RFC3339, "2006-01-02T15:04:05", "2006"}
+
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses value in any of the accepted layouts; "" is the zero Date.
+func ParseDate(value string) (Date, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Date{}, nil
+	}
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return NewDate(parsed.Date()), nil
+		}
+	}
+	return Date{}, fmt.Errorf("incorrect date %q, expected DD.MM.YYYY, YYYY-MM-DD or YYYY", value)
 }
 
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == nil {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(*value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d *Date) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = NewDate(v.Date())
+		return nil
+	default:
+		return fmt.Errorf("unsupported date type %T", value)
+	}
+}
+
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.Time, nil
+}
+
+const (
+	SongStatusEnriched          = "enriched"
+	SongStatusPendingEnrichment = "pending_enrichment"
+	SongStatusEnrichmentFailed  = "enrichment_failed"
+)
+
 type SongUpdate struct {
 	GroupName   *string `json:"group"`
 	Song        *string `json:"song"`
-	ReleaseDate *string `json:"releaseDate"`
+	ReleaseDate *Date   `json:"releaseDate" swaggertype:"string" example:"16.07.2006"`
 	Text        *string `json:"text"`
 	Link        *string `json:"link"`
 }
 
+// SongFilterParams are the raw GetSongs filter query params.
+type SongFilterParams struct {
+	GroupName    string
+	Song         string
+	ReleaseDate  string
+	ReleasedFrom string
+	ReleasedTo   string
+	Year         string
+	Decade       string
+	ArtistID     string
+	Text         string
+	Link         string
+	// Filter is an expression of the filter package.
+	Filter string
+}
+
+// SongFilters are the parsed GetSongs filters; zero values do not filter.
+// The release date bounds are inclusive.
+type SongFilters struct {
+	ArtistID     uint
+	GroupName    string
+	Song         string
+	Text         string
+	Link         string
+	ReleasedFrom Date
+	ReleasedTo   Date
+	Expr         filter.Expr
+}
+
+// IsZero tells whether f filters nothing. Expr may hold functions, so
+// SongFilters must not be compared with ==.
+func (f SongFilters) IsZero() bool {
+	return f.ArtistID == 0 && f.GroupName == "" && f.Song == "" && f.Text == "" && f.Link == "" &&
+		f.ReleasedFrom.IsZero() && f.ReleasedTo.IsZero() && f.Expr == nil
+}
+
+// Artist is a performer songs reference. SongCount is computed on read.
+type Artist struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `json:"name"`
+	SongCount int64     `gorm:"->;-:migration" json:"songCount"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ArtistRequest struct {
+	Name string `json:"name"`
+}
+
+type ArtistsPage struct {
+	Items []Artist `json:"items"`
+	Total int64    `json:"total"`
+	Page  int      `json:"page"`
+	Limit int      `json:"limit"`
+}
+
+// Album is a release of songs. The artist, track count and tracks are
+// computed on read; Tracks only by GetAlbum.
+type Album struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	Title       string       `json:"title"`
+	ArtistID    *uint        `json:"artistId,omitempty"`
+	Artist      string       `gorm:"->;-:migration" json:"artist"`
+	ReleaseDate Date         `gorm:"type:date" json:"releaseDate" swaggertype:"string" example:"16.07.2006"`
+	CoverLink   string       `json:"coverLink"`
+	TrackCount  int64        `gorm:"->;-:migration" json:"trackCount"`
+	Tracks      []AlbumTrack `gorm:"-" json:"tracks,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+}
+
+// AlbumTrack places a song on an album. Group and Song are read from the
+// song.
+type AlbumTrack struct {
+	AlbumID     uint   `gorm:"primaryKey" json:"-"`
+	SongID      uint   `gorm:"primaryKey" json:"songId"`
+	DiscNumber  int    `json:"discNumber"`
+	TrackNumber int    `json:"trackNumber"`
+	GroupName   string `gorm:"->;-:migration" json:"group"`
+	Song        string `gorm:"->;-:migration" json:"song"`
+}
+
+// AlbumRequest creates an album. Artist is a name, found or created like
+// the group of a song. A zero disc number is 1; a zero track number is the
+// next one on its disc.
+type AlbumRequest struct {
+	Title       string              `json:"title"`
+	Artist      string              `json:"artist"`
+	ReleaseDate Date                `json:"releaseDate" swaggertype:"string" example:"16.07.2006"`
+	CoverLink   string              `json:"coverLink"`
+	Tracks      []AlbumTrackRequest `json:"tracks"`
+}
+
+type AlbumTrackRequest struct {
+	SongID      uint `json:"songId"`
+	DiscNumber  int  `json:"discNumber"`
+	TrackNumber int  `json:"trackNumber"`
+}
+
+type AlbumsPage struct {
+	Items []Album `json:"items"`
+	Total int64   `json:"total"`
+	Page  int     `json:"page"`
+	Limit int     `json:"limit"`
+}
+
+// Playlist is an ordered list of songs. Version grows with every change;
+// edits may pass the version they are based on and fail if it moved on.
+// Entries are filled only for a single playlist.
+type Playlist struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Version     int             `json:"version"`
+	SongCount   int64           `gorm:"->;-:migration" json:"songCount"`
+	Entries     []PlaylistEntry `gorm:"-" json:"entries,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+// PlaylistEntry is a song at a 1-based position of a playlist. Group, Song
+// and Link are read from the song.
+type PlaylistEntry struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	PlaylistID uint      `json:"-"`
+	SongID     uint      `json:"songId"`
+	Position   int       `json:"position"`
+	AddedAt    time.Time `gorm:"->;-:migration" json:"addedAt"`
+	GroupName  string    `gorm:"->;-:migration" json:"group"`
+	Song       string    `gorm:"->;-:migration" json:"song"`
+	Link       string    `gorm:"->;-:migration" json:"link"`
+}
+
+type PlaylistRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PlaylistUpdate changes the non-nil fields. Version, if set, must be the
+// current version of the playlist; so in the other playlist edits.
+type PlaylistUpdate struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Version     *int    `json:"version"`
+}
+
+// PlaylistEntryRequest adds a song at Position, shifting the entries from
+// there down; a zero position appends.
+type PlaylistEntryRequest struct {
+	SongID   uint `json:"songId"`
+	Position int  `json:"position"`
+	Version  *int `json:"version"`
+}
+
+type PlaylistMoveRequest struct {
+	Position int  `json:"position"`
+	Version  *int `json:"version"`
+}
+
+// PlaylistOrderRequest lists every entry id of the playlist in the new
+// order.
+type PlaylistOrderRequest struct {
+	EntryIDs []uint `json:"entryIds"`
+	Version  *int   `json:"version"`
+}
+
+type PlaylistsPage struct {
+	Items []Playlist `json:"items"`
+	Total int64      `json:"total"`
+	Page  int        `json:"page"`
+	Limit int        `json:"limit"`
+}
+
+// Statuses of imported playlist tracks.
+const (
+	ImportMatched = "matched"
+	ImportCreated = "created"
+	ImportFailed  = "failed"
+)
+
+// PlaylistImportResponse is the playlist made of an imported file and what
+// happened to each of its tracks; failed tracks are left out of the
+// playlist.
+type PlaylistImportResponse struct {
+	Playlist Playlist        `json:"playlist"`
+	Tracks   []ImportedTrack `json:"tracks"`
+}
+
+type ImportedTrack struct {
+	Group    string `json:"group"`
+	Song     string `json:"song"`
+	Location string `json:"location,omitempty"`
+	SongID   uint   `json:"songId,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PlaylistFile is an exported playlist file.
+type PlaylistFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Statuses of song imports.
+const (
+	SongImportRunning = "running"
+	SongImportDone    = "done"
+	SongImportFailed  = "failed"
+)
+
+// Statuses of song import rows.
+const (
+	RowInserted         = "inserted"
+	RowDuplicate        = "duplicate"
+	RowEnrichmentFailed = "enrichment_failed"
+	RowInvalid          = "invalid"
+)
+
+// SongImport is a bulk import of a song file and its report. The counters
+// grow while the import runs; Rows are filled only for a single import.
+type SongImport struct {
+	ID               uint            `gorm:"primaryKey" json:"id"`
+	Status           string          `json:"status"`
+	Format           string          `json:"format"`
+	Total            int             `json:"total"`
+	Inserted         int             `json:"inserted"`
+	Duplicates       int             `json:"duplicates"`
+	EnrichmentFailed int             `json:"enrichmentFailed"`
+	Invalid          int             `json:"invalid"`
+	Error            string          `json:"error,omitempty"`
+	Rows             []SongImportRow `gorm:"-" json:"rows,omitempty"`
+	CreatedAt        time.Time       `json:"createdAt"`
+	UpdatedAt        time.Time       `json:"updatedAt"`
+	FinishedAt       *time.Time      `json:"finishedAt,omitempty"`
+}
+
+// SongImportRow is what became of a row of an imported file. SongID is the
+// inserted song, or the existing one for a duplicate. Details is the song
+// to insert, carried from enrichment to the store.
+type SongImportRow struct {
+	ImportID  uint   `gorm:"primaryKey" json:"-"`
+	Line      int    `gorm:"primaryKey" json:"line"`
+	GroupName string `json:"group"`
+	Song      string `json:"song"`
+	SongID    *uint  `json:"songId,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	Details   *Song  `gorm:"-" json:"-"`
+}
+
+// SongExport is a file of songs written as it is sent: the songs are read
+// only when Write is called, and only as fast as w takes them.
+type SongExport struct {
+	Name        string
+	ContentType string
+	Write       func(w io.Writer) error
+}
+
+// SongAlbum is album info an enrichment provider knows for a song. A zero
+// track number appends the song to its disc.
+type SongAlbum struct {
+	Title       string `json:"title"`
+	ReleaseDate Date   `json:"releaseDate" swaggertype:"string" example:"16.07.2006"`
+	CoverLink   string `json:"coverLink"`
+	DiscNumber  int    `json:"discNumber"`
+	TrackNumber int    `json:"trackNumber"`
+}
+
 type SongRequest struct {
 	Group string `json:"group"`
 	Song  string `json:"song"`
@@ -26,8 +443,147 @@ type TextResponse struct {
 	Text string `json:"text"`
 }
 
+type Verse struct {
+	Index     int      `json:"index"`
+	StartLine int      `json:"startLine"`
+	EndLine   int      `json:"endLine"`
+	Lines     []string `json:"lines"`
+}
+
+type VersesResponse struct {
+	Verses []Verse `json:"verses"`
+	Page   int     `json:"page"`
+	Limit  int     `json:"limit"`
+	Total  int     `json:"total"`
+}
+
+// SortKey is a song listing sort field, by its json name.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// SongCursor is the decoded position of a keyset page: the sort the page was
+// listed with and the sort key values, id last, of the row the page starts
+// after, or before when Backward is set.
+type SongCursor struct {
+	Sort     string   `json:"s,omitempty"`
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// SongsPage is a page of the song listing. Page is set in page mode and
+// the cursors in cursor mode; they are opaque, pass one back as the cursor
+// query param. Next and Prev are ready-made links to the neighbour pages.
+// Total is left out when counting was not requested.
+type SongsPage struct {
+	Items          []Song `json:"items"`
+	Total          *int64 `json:"total,omitempty"`
+	TotalEstimated bool   `json:"totalEstimated,omitempty"`
+	Page           int    `json:"page,omitempty"`
+	Limit          int    `json:"limit"`
+	NextCursor     string `json:"nextCursor,omitempty"`
+	PrevCursor     string `json:"prevCursor,omitempty"`
+	Next           string `json:"next,omitempty"`
+	Prev           string `json:"prev,omitempty"`
+}
+
+type LyricsSection struct {
+	ID       uint   `gorm:"primaryKey" json:"-"`
+	SongID   uint   `json:"-"`
+	Position int    `json:"position"`
+	Kind     string `json:"kind"`
+	Number   int    `json:"number"`
+	Text     string `json:"text"`
+}
+
+// LyricsTiming is the timestamp of a line of Song.Text, 1-based.
+type LyricsTiming struct {
+	SongID uint `gorm:"primaryKey"`
+	Line   int  `gorm:"primaryKey"`
+	TimeMs int
+}
+
+type ActiveLineResponse struct {
+	Active   bool   `json:"active"`
+	Line     int    `json:"line,omitempty"`
+	Text     string `json:"text"`
+	Time     string `json:"time,omitempty"`
+	NextTime string `json:"nextTime,omitempty"`
+}
+
+type SectionsResponse struct {
+	Sections []LyricsSection `json:"sections"`
+}
+
+type SearchResult struct {
+	Song     `gorm:"embedded"`
+	Rank     float32 `json:"rank"`
+	Headline string  `json:"headline"`
+}
+
+// SearchPage is a page of full-text search results in the envelope of
+// SongsPage; total is always exact.
+type SearchPage struct {
+	Items []SearchResult `json:"items"`
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+	Next  string         `json:"next,omitempty"`
+	Prev  string         `json:"prev,omitempty"`
+}
+
+type FuzzyResult struct {
+	Song  `gorm:"embedded"`
+	Score float32 `json:"score"`
+}
+
+// FuzzyPage is a page of fuzzy search results in the envelope of SongsPage;
+// total is always exact.
+type FuzzyPage struct {
+	Items []FuzzyResult `json:"items"`
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+	Next  string        `json:"next,omitempty"`
+	Prev  string        `json:"prev,omitempty"`
+}
+
+// DuplicatePair is two songs that are likely one. TextScore is the
+// similarity of their lyrics, when both have some.
+type DuplicatePair struct {
+	SongID    uint     `json:"songId"`
+	OtherID   uint     `json:"otherId"`
+	NameScore float32  `json:"nameScore"`
+	TextScore *float32 `json:"textScore,omitempty"`
+	Score     float32  `json:"score"`
+}
+
+// DuplicateCluster is songs linked by duplicate pairs, directly or through
+// each other. Score is that of its best pair.
+type DuplicateCluster struct {
+	Songs []Song          `json:"songs"`
+	Pairs []DuplicatePair `json:"pairs"`
+	Score float32         `json:"score"`
+}
+
+type DuplicatesPage struct {
+	Items []DuplicateCluster `json:"items"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+}
+
+// MergeRequest lists the songs to merge into the song of the path.
+type MergeRequest struct {
+	IDs []uint `json:"ids"`
+}
+
 type InsertResponse struct {
-	ID int `json:"id"`
+	ID      int               `json:"id"`
+	Status  string            `json:"status,omitempty"`
+	JobID   int               `json:"jobId,omitempty"`
+	Sources map[string]string `json:"sources,omitempty"`
 }
 
 type UpdateResponse struct {
@@ -37,3 +593,28 @@ type UpdateResponse struct {
 type DeleteResponse struct {
 	Status bool `json:"status"`
 }
+
+type EnrichmentJob struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	SongID      uint       `json:"songId"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"maxAttempts"`
+	LastError   string     `json:"lastError,omitempty"`
+	RunAt       time.Time  `json:"runAt"`
+	LockedUntil *time.Time `json:"-"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+const (
+	JobStatusQueued  = "queued"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusDead    = "dead"
+)
+
+type RetryJobResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
@@ -1,13 +1,59 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrSongNotFound      = errors.New("song not found")
-	ErrAnotherStatucCode = errors.New("error with sending request")
-	ErrIncorrectRequest  = errors.New("incorrect request")
+	ErrSongNotFound       = errors.New("song not found")
+	ErrAnotherStatucCode  = errors.New("error with sending request")
+	ErrIncorrectRequest   = errors.New("incorrect request")
+	ErrSongApiUnavailable = errors.New("song api is unavailable")
+	ErrSongInfoNotFound   = errors.New("song info not found")
+	ErrNoSyncedLyrics     = errors.New("song has no synced lyrics")
+	ErrJobNotFound        = errors.New("job not found")
+	ErrJobNotRetryable    = errors.New("job is not in dead state")
+	ErrJobLeaseLost       = errors.New("job lease expired and the job was taken over")
+	ErrSchemaOutdated     = errors.New("database scheme is behind, run migrations")
+	ErrIncorrectFilter    = errors.New("incorrect filter")
+	ErrArtistNotFound     = errors.New("artist not found")
+	ErrArtistExists       = errors.New("artist with this name already exists")
+	ErrArtistHasSongs     = errors.New("artist has songs")
+	ErrArtistHasAlbums    = errors.New("artist has albums")
+	ErrAlbumNotFound      = errors.New("album not found")
+	ErrAlbumExists        = errors.New("artist already has an album with this title")
+	ErrPlaylistNotFound   = errors.New("playlist not found")
+	ErrEntryNotFound      = errors.New("playlist entry not found")
+	ErrPlaylistChanged    = errors.New("playlist was changed, reload it")
+	ErrEntriesMismatch    = errors.New("entries do not match the playlist")
+	ErrIncorrectFile      = errors.New("incorrect playlist file")
+	ErrIncorrectSongFile  = errors.New("incorrect song file")
+	ErrImportNotFound     = errors.New("song import not found")
+	ErrSongExists         = errors.New("song already exists")
+	ErrHandlersAbandoned  = errors.New("handlers are still running after shutdown")
 )
 
+// SongExistsError is ErrSongExists naming the song that has the name.
+type SongExistsError struct {
+	ID uint
+}
+
+func (e *SongExistsError) Error() string {
+	return fmt.Sprintf("song already exists with id %d", e.ID)
+}
+
+func (e *SongExistsError) Is(target error) bool {
+	return target == ErrSongExists
+}
+
 type ErrorMessage struct {
 	Error string `json:"error"`
 }
+
+// SongExistsMessage answers an insert or rename that clashes with an
+// existing song; ID is that song, when it is known.
+type SongExistsMessage struct {
+	Error string `json:"error"`
+	ID    uint   `json:"id,omitempty"`
+}
@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+var comparisonSQL = map[string]string{
+	OpEq: " = ?",
+	OpNe: " <> ?",
+	OpGt: " > ?",
+	OpGe: " >= ?",
+	OpLt: " < ?",
+	OpLe: " <= ?",
+}
+
+// likeEscaper escapes LIKE wildcards in contains/startswith/endswith values.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Clause translates a parsed filter into a gorm clause for Where. Every
+// node is parenthesized, so operator precedence in the column expressions
+// never leaks. The tree is nested in clause.Expr rather than built with
+// clause.And, clause.Or and clause.Not, which flatten and rewrite their
+// operands: clause.Not of an AND turns it into an OR of the negations, and
+// of an OR nested in it too. A nil Expr yields nil.
+func Clause(expr Expr) clause.Expression {
+	switch e := expr.(type) {
+	case And:
+		return clause.Expr{SQL: "(? AND ?)", Vars: []interface{}{Clause(e.Left), Clause(e.Right)}}
+	case Or:
+		return clause.Expr{SQL: "(? OR ?)", Vars: []interface{}{Clause(e.Left), Clause(e.Right)}}
+	case Not:
+		return clause.Expr{SQL: "(NOT ?)", Vars: []interface{}{Clause(e.Expr)}}
+	case Empty:
+		sql := "(" + e.Field.Column + " IS NULL"
+		if e.Field.Kind == KindText {
+			sql += " OR " + e.Field.Column + " = ''"
+		}
+		sql += ")"
+		if e.Negated {
+			sql = "NOT " + sql
+		}
+		return clause.Expr{SQL: "(" + sql + ")"}
+	case Compare:
+		switch e.Op {
+		case OpContains:
+			return like(e.Field, "%"+likeEscaper.Replace(e.Value.(string))+"%")
+		case OpStartsWith:
+			return like(e.Field, likeEscaper.Replace(e.Value.(string))+"%")
+		case OpEndsWith:
+			return like(e.Field, "%"+likeEscaper.Replace(e.Value.(string)))
+		}
+		return clause.Expr{SQL: "(" + e.Field.Column + comparisonSQL[e.Op] + ")", Vars: []interface{}{e.Value}}
+	}
+	return nil
+}
+
+// like matches case-insensitively, unlike eq.
+func like(field Field, pattern string) clause.Expression {
+	return clause.Expr{SQL: "(" + field.Column + ` ILIKE ? ESCAPE '\')`, Vars: []interface{}{pattern}}
+}
@@ -0,0 +1,304 @@
+// Package filter parses filter expressions such as
+//
+//	group eq "Muse" and (year ge 2000 or text contains "love")
+//
+// into a tree checked against an allow-list of fields, and translates the
+// tree into gorm clauses. Only allow-listed column expressions reach SQL;
+// every value is a bound parameter.
+//
+// Grammar, keywords are case-insensitive:
+//
+//	expr       = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" expr ")" | comparison
+//	comparison = field op value | field "is" [ "not" ] "empty"
+//	op         = eq | ne | gt | ge | lt | le | contains | startswith | endswith
+//	value      = "string" | number
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of a field; it decides the allowed operators and values.
+type Kind int
+
+const (
+	KindText Kind = iota
+	KindNumber
+	KindDate
+)
+
+// Field is an allow-listed filter field. Column is the trusted SQL
+// expression it is compared by. Value, if set, converts a literal into the
+// bound parameter, e.g. a date string into a date; its error is reported at
+// the literal.
+type Field struct {
+	Column string
+	Kind   Kind
+	Value  func(literal string) (interface{}, error)
+}
+
+// Operators of comparisons.
+const (
+	OpEq         = "eq"
+	OpNe         = "ne"
+	OpGt         = "gt"
+	OpGe         = "ge"
+	OpLt         = "lt"
+	OpLe         = "le"
+	OpContains   = "contains"
+	OpStartsWith = "startswith"
+	OpEndsWith   = "endswith"
+)
+
+var kindOperators = map[Kind][]string{
+	KindText:   {OpEq, OpNe, OpGt, OpGe, OpLt, OpLe, OpContains, OpStartsWith, OpEndsWith},
+	KindNumber: {OpEq, OpNe, OpGt, OpGe, OpLt, OpLe},
+	KindDate:   {OpEq, OpNe, OpGt, OpGe, OpLt, OpLe},
+}
+
+// Limits that keep hostile input from exhausting the parser.
+const (
+	MaxLength = 2000
+	maxDepth  = 32
+)
+
+// Expr is a node of a parsed filter.
+type Expr interface {
+	node()
+}
+
+type And struct {
+	Left, Right Expr
+}
+
+type Or struct {
+	Left, Right Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+// Compare is field op value.
+type Compare struct {
+	Field Field
+	Op    string
+	Value interface{}
+}
+
+// Empty is field is [not] empty: NULL, or "" for text fields.
+type Empty struct {
+	Field   Field
+	Negated bool
+}
+
+func (And) node()     {}
+func (Or) node()      {}
+func (Not) node()     {}
+func (Compare) node() {}
+func (Empty) node()   {}
+
+// SyntaxError points at the token the filter went wrong at.
+type SyntaxError struct {
+	// Pos is the 1-based rune offset in the filter.
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+// Parse parses input against the allow-listed fields. Errors are
+// *SyntaxError. An empty input is a nil Expr.
+func Parse(input string, fields map[string]Field) (Expr, error) {
+	if len(input) > MaxLength {
+		return nil, &SyntaxError{Pos: 1, Msg: fmt.Sprintf("filter is longer than %d bytes", MaxLength)}
+	}
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	if tokens[0].kind == tokenEOF {
+		return nil, nil
+	}
+	p := &parser{tokens: tokens, fields: fields}
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, p.errorf(next, "unexpected %s, expected and, or or end of filter", next)
+	}
+	return expr, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	fields map[string]Field
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword consumes the next token if it is the keyword word.
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokenIdent && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr(depth int) (Expr, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (Expr, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary(depth int) (Expr, error) {
+	if depth > maxDepth {
+		return nil, p.errorf(p.peek(), "filter is nested deeper than %d levels", maxDepth)
+	}
+	if p.keyword("not") {
+		expr, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: expr}, nil
+	}
+	if p.peek().kind == tokenLParen {
+		open := p.next()
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorf(closing, "unexpected %s, expected \")\" closing \"(\" at position %d", closing, open.pos)
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	name := p.next()
+	if name.kind != tokenIdent || isKeyword(name.text) {
+		return nil, p.errorf(name, "unexpected %s, expected field name", name)
+	}
+	field, ok := p.fields[name.text]
+	if !ok {
+		return nil, p.errorf(name, "unknown field %q, expected one of %s", name.text, fieldNames(p.fields))
+	}
+
+	if p.keyword("is") {
+		negated := p.keyword("not")
+		if !p.keyword("empty") {
+			t := p.peek()
+			return nil, p.errorf(t, "unexpected %s, expected empty", t)
+		}
+		return Empty{Field: field, Negated: negated}, nil
+	}
+
+	op := p.next()
+	if op.kind != tokenIdent || !allowed(field.Kind, strings.ToLower(op.text)) {
+		return nil, p.errorf(op, "unexpected %s, expected one of %s or is for field %q",
+			op, strings.Join(kindOperators[field.Kind], ", "), name.text)
+	}
+
+	literal := p.next()
+	value, err := literalValue(field, literal)
+	if err != nil {
+		return nil, p.errorf(literal, "%s", err)
+	}
+	return Compare{Field: field, Op: strings.ToLower(op.text), Value: value}, nil
+}
+
+// literalValue checks literal against the field kind and converts it.
+func literalValue(field Field, literal token) (interface{}, error) {
+	switch {
+	case literal.kind == tokenNumber && field.Kind == KindNumber:
+		number, err := strconv.ParseFloat(literal.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect number %s", literal)
+		}
+		if field.Value != nil {
+			return field.Value(literal.value)
+		}
+		return number, nil
+	case literal.kind == tokenString && field.Kind != KindNumber:
+		if field.Value != nil {
+			return field.Value(literal.value)
+		}
+		return literal.value, nil
+	case field.Kind == KindNumber:
+		return nil, fmt.Errorf("unexpected %s, expected number", literal)
+	default:
+		return nil, fmt.Errorf("unexpected %s, expected \"string\"", literal)
+	}
+}
+
+func allowed(kind Kind, op string) bool {
+	return slices.Contains(kindOperators[kind], op)
+}
+
+func isKeyword(word string) bool {
+	switch strings.ToLower(word) {
+	case "and", "or", "not", "is", "empty":
+		return true
+	}
+	return false
+}
+
+func fieldNames(fields map[string]Field) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
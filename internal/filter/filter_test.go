@@ -0,0 +1,250 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+var testFields = map[string]Field{
+	"group":    {Column: "songs.group_name", Kind: KindText},
+	"song":     {Column: "songs.song", Kind: KindText},
+	"year":     {Column: "extract(year from songs.release_date)", Kind: KindNumber},
+	"released": {Column: "songs.release_date", Kind: KindDate},
+}
+
+// show prints a parsed filter with every node parenthesized, so that tests
+// can check how it was grouped.
+func show(expr Expr) string {
+	switch e := expr.(type) {
+	case And:
+		return "(" + show(e.Left) + " and " + show(e.Right) + ")"
+	case Or:
+		return "(" + show(e.Left) + " or " + show(e.Right) + ")"
+	case Not:
+		return "(not " + show(e.Expr) + ")"
+	case Empty:
+		if e.Negated {
+			return e.Field.Column + " is not empty"
+		}
+		return e.Field.Column + " is empty"
+	case Compare:
+		return fmt.Sprintf("%s %s %v", e.Field.Column, e.Op, e.Value)
+	}
+	return "<nil>"
+}
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{``, `<nil>`},
+		{`group eq "a"`, `songs.group_name eq a`},
+		{`group eq "a" or song eq "b" and year gt 2000`,
+			`(songs.group_name eq a or (songs.song eq b and extract(year from songs.release_date) gt 2000))`},
+		{`not group eq "a" or song eq "b"`,
+			`((not songs.group_name eq a) or songs.song eq b)`},
+		{`not (group eq "a" or song eq "b")`,
+			`(not (songs.group_name eq a or songs.song eq b))`},
+		{`not not group is empty and song is not empty`,
+			`((not (not songs.group_name is empty)) and songs.song is not empty)`},
+		{`group eq "a" and song eq "b" and year eq 1`,
+			`((songs.group_name eq a and songs.song eq b) and extract(year from songs.release_date) eq 1)`},
+		{`GROUP EQ "a" OR NOT song CONTAINS "x"`, ``},
+		{`group eq "say \"hi\" \\ bye"`, `songs.group_name eq say "hi" \ bye`},
+	}
+	for _, test := range tests {
+		expr, err := Parse(test.input, testFields)
+		if test.want == "" {
+			// field names are case-sensitive, keywords are not
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Pos != 1 {
+				t.Errorf("Parse(%q) error = %v, want unknown field at 1", test.input, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", test.input, err)
+			continue
+		}
+		if got := show(expr); got != test.want {
+			t.Errorf("Parse(%q) = %s, want %s", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		pos   int
+	}{
+		{"bad character", `group eq "a" & song eq "b"`, 14},
+		{"bad character after multibyte runes", `group eq "ёж" $`, 15},
+		{"unclosed string", `group eq "abc`, 10},
+		{"unknown escape", `group eq "a\n"`, 12},
+		{"unknown field", `artist eq "a"`, 1},
+		{"keyword as field", `and eq "a"`, 1},
+		{"operator not allowed for numbers", `year contains 1`, 6},
+		{"string for a number", `year eq "2000"`, 9},
+		{"number for a string", `group eq 1`, 10},
+		{"is without empty", `group is "a"`, 10},
+		{"missing value", `group eq`, 9},
+		{"unclosed paren", `(group eq "a"`, 14},
+		{"trailing tokens", `group eq "a" song`, 14},
+		{"dangling and", `group eq "a" and`, 17},
+		{"parens nested too deep", strings.Repeat("(", maxDepth+8) + `group eq "a"` + strings.Repeat(")", maxDepth+8), maxDepth + 2},
+		{"not nested too deep", strings.Repeat("not ", maxDepth+8) + `group eq "a"`, 4*(maxDepth+1) + 1},
+		{"too long", `group eq "` + strings.Repeat("a", MaxLength) + `"`, 1},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.input, testFields)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%s: Parse(%q) error = %v, want *SyntaxError", test.name, test.input, err)
+			continue
+		}
+		if syntaxErr.Pos != test.pos {
+			t.Errorf("%s: Parse(%q) error at %d (%v), want at %d", test.name, test.input, syntaxErr.Pos, syntaxErr, test.pos)
+		}
+	}
+}
+
+func TestClause(t *testing.T) {
+	tests := []struct {
+		input string
+		sql   string
+		vars  []interface{}
+	}{
+		{
+			`group contains "50%_off" and not (year ge 2000 or song is empty)`,
+			`((songs.group_name ILIKE $1 ESCAPE '\') AND (NOT ((extract(year from songs.release_date) >= $2) OR ((songs.song IS NULL OR songs.song = '')))))`,
+			[]interface{}{`%50\%\_off%`, float64(2000)},
+		},
+		{
+			`not (group eq "x" and (year ge 2000 or year le 1990))`,
+			`(NOT ((songs.group_name = $1) AND ((extract(year from songs.release_date) >= $2) OR (extract(year from songs.release_date) <= $3))))`,
+			[]interface{}{"x", float64(2000), float64(1990)},
+		},
+		{
+			`not not group eq "x" or not song is not empty`,
+			`((NOT (NOT (songs.group_name = $1))) OR (NOT (NOT (songs.song IS NULL OR songs.song = ''))))`,
+			[]interface{}{"x"},
+		},
+		{
+			`group eq "a" or song eq "b" and year ne 1`,
+			`((songs.group_name = $1) OR ((songs.song = $2) AND (extract(year from songs.release_date) <> $3)))`,
+			[]interface{}{"a", "b", float64(1)},
+		},
+	}
+	for _, test := range tests {
+		expr, err := Parse(test.input, testFields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sql, vars := build(t, Clause(expr))
+		if sql != test.sql {
+			t.Errorf("Parse(%q): sql = %s, want %s", test.input, sql, test.sql)
+		}
+		if fmt.Sprint(vars) != fmt.Sprint(test.vars) {
+			t.Errorf("Parse(%q): vars = %v, want %v", test.input, vars, test.vars)
+		}
+	}
+	if Clause(nil) != nil {
+		t.Errorf("Clause(nil) is not nil")
+	}
+}
+
+var (
+	dryDBOnce sync.Once
+	dryDB     *gorm.DB
+	dryDBErr  error
+)
+
+// build writes the WHERE condition of a query filtered by expression, with
+// gorm's own builder and the postgres dialect; nothing is sent anywhere.
+func build(t testing.TB, expression clause.Expression) (string, []interface{}) {
+	t.Helper()
+	dryDBOnce.Do(func() {
+		dryDB, dryDBErr = gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+			DryRun:                 true,
+			DisableAutomaticPing:   true,
+			SkipDefaultTransaction: true,
+			Logger:                 gormLogger.Discard,
+		})
+	})
+	if dryDBErr != nil {
+		t.Fatal(dryDBErr)
+	}
+	var rows []map[string]interface{}
+	stmt := dryDB.Table("songs").Where(expression).Find(&rows).Statement
+	sql, ok := strings.CutPrefix(stmt.SQL.String(), `SELECT * FROM "songs" WHERE `)
+	if !ok {
+		t.Fatalf("unexpected query %s", stmt.SQL.String())
+	}
+	return sql, stmt.Vars
+}
+
+// testColumns are the columns of testFields, longest first, so that none is
+// cut out of another.
+var testColumns = func() []string {
+	var columns []string
+	for _, field := range testFields {
+		columns = append(columns, field.Column)
+	}
+	slices.SortFunc(columns, func(a, b string) int { return len(b) - len(a) })
+	return columns
+}()
+
+// fixedSQL is all the SQL Clause may write besides the field columns.
+var fixedSQL = regexp.MustCompile(`^(\s|[()=<>]|\$\d+|''|AND|OR|NOT|IS|NULL|ILIKE|ESCAPE|'\\')*$`)
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		``,
+		`group eq "Muse"`,
+		`group eq "Muse" and (year ge 2000 or song contains "love")`,
+		`not released lt "2000-01-01" or song is not empty`,
+		`group eq "a\"b\\c"`,
+		`group eq "); DROP TABLE songs; --"`,
+		`songs.group_name eq "a"`,
+		`(((((group eq "a")))))`,
+		`year eq -1.5.5`,
+		`group eq "`,
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		expr, err := Parse(input, testFields)
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want *SyntaxError", input, err)
+			}
+			if syntaxErr.Pos < 1 || syntaxErr.Pos > len([]rune(input))+1 {
+				t.Fatalf("Parse(%q) error at %d, outside of the input", input, syntaxErr.Pos)
+			}
+			return
+		}
+		if expr == nil {
+			return
+		}
+		sql, _ := build(t, Clause(expr))
+		for _, column := range testColumns {
+			sql = strings.ReplaceAll(sql, column, "")
+		}
+		if !fixedSQL.MatchString(sql) {
+			t.Fatalf("Parse(%q) put input into SQL: %s", input, sql)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenLParen
+	tokenRParen
+)
+
+// token is a lexeme of a filter expression. Pos is its 1-based rune offset
+// in the input, reported in errors.
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.value)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lex splits input into tokens: identifiers and keywords, "double quoted"
+// strings with \" and \\ escapes, numbers and parentheses.
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := make([]token, 0)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: start + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: start + 1})
+			i++
+		case r == '"':
+			var value strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' {
+					if i+1 >= len(runes) || (runes[i+1] != '"' && runes[i+1] != '\\') {
+						return nil, &SyntaxError{Pos: i + 1, Msg: `unknown escape, only \" and \\ are allowed`}
+					}
+					value.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, &SyntaxError{Pos: start + 1, Msg: "string is not closed"}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[start:i]), value: value.String(), pos: start + 1})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: text, pos: start + 1})
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			text := string(runes[start:i])
+			tokens = append(tokens, token{kind: tokenIdent, text: text, value: text, pos: start + 1})
+		default:
+			return nil, &SyntaxError{Pos: start + 1, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
@@ -3,8 +3,10 @@ package handler
 import (
 	"context"
 	_ "effectiveMobile/docs"
+	"effectiveMobile/internal/config"
 	"effectiveMobile/internal/logger"
 	"effectiveMobile/internal/service"
+	"expvar"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -14,11 +16,17 @@ import (
 
 type Handler struct {
 	service *service.Service
+	// importMaxBytes and playlistImportMaxBytes cap the bodies of a bulk
+	// song import and of a playlist import.
+	importMaxBytes         int64
+	playlistImportMaxBytes int64
 }
 
-func NewHandler(service *service.Service) *Handler {
+func NewHandler(service *service.Service, cfg config.Config) *Handler {
 	return &Handler{
-		service: service,
+		service:                service,
+		importMaxBytes:         int64(cfg.ImportMaxMB) << 20,
+		playlistImportMaxBytes: int64(cfg.PlaylistImportMaxMB) << 20,
 	}
 }
 
@@ -29,13 +37,47 @@ func (h *Handler) InitRoutes(ctx context.Context) http.Handler {
 	router.Use(logger.LoggerMiddleware(log))
 	log.Debugw("swagger is initialized")
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
 	api := router.Group("/api")
 	{
 		api.GET("/getsongs", h.GetSongs)
+		api.GET("/search", h.SearchLyrics)
+		api.GET("/search/fuzzy", h.FuzzySearch)
 		api.GET("/gettext/:id", h.GetTextSong)
 		api.DELETE("deletesong/:id", h.DeleteSong)
 		api.PATCH("updatesong/:id", h.UpdateSong)
 		api.POST("insertsong/", h.InsertSong)
+		api.GET("/songs/export", h.ExportSongs)
+		api.POST("/songs/import", h.ImportSongs)
+		api.GET("/songs/imports/:id", h.GetImport)
+		api.GET("/songs/duplicates", h.FindDuplicates)
+		api.POST("/songs/:id/merge", h.MergeSongs)
+		api.POST("/songs/:id/refresh", h.RefreshSong)
+		api.GET("/songs/:id/sections", h.GetSections)
+		api.PUT("/songs/:id/lrc", h.ImportLRC)
+		api.GET("/songs/:id/lrc", h.ExportLRC)
+		api.GET("/songs/:id/lyrics/at", h.GetActiveLine)
+		api.POST("/artists", h.InsertArtist)
+		api.GET("/artists", h.GetArtists)
+		api.GET("/artists/:id", h.GetArtist)
+		api.PATCH("/artists/:id", h.RenameArtist)
+		api.DELETE("/artists/:id", h.DeleteArtist)
+		api.POST("/albums", h.InsertAlbum)
+		api.GET("/albums", h.GetAlbums)
+		api.GET("/albums/:id", h.GetAlbum)
+		api.POST("/playlists", h.InsertPlaylist)
+		api.GET("/playlists", h.GetPlaylists)
+		api.GET("/playlists/:id", h.GetPlaylist)
+		api.PATCH("/playlists/:id", h.UpdatePlaylist)
+		api.DELETE("/playlists/:id", h.DeletePlaylist)
+		api.POST("/playlists/:id/entries", h.AddPlaylistEntry)
+		api.POST("/playlists/:id/entries/:entryId/move", h.MovePlaylistEntry)
+		api.DELETE("/playlists/:id/entries/:entryId", h.RemovePlaylistEntry)
+		api.PUT("/playlists/:id/order", h.ReorderPlaylist)
+		api.GET("/playlists/:id/export", h.ExportPlaylist)
+		api.POST("/playlists/import", h.ImportPlaylist)
+		api.GET("/jobs/:id", h.GetJob)
+		api.POST("/jobs/:id/retry", h.RetryJob)
 	}
 	log.Debugw("routes are initialized")
 	return router
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary InsertAlbum
+// @Tags albums
+// @Description create album with its tracklist
+// @ID insert album
+// @Accept json
+// @Produce json
+// @Param input body entities.AlbumRequest true "Album"
+// @Success 200 {object} entities.Album
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/albums [post]
+func (h *Handler) InsertAlbum(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	var req entities.AlbumRequest
+
+	err := c.ShouldBindJSON(&req)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with binding json",
+		})
+		log.Errorw("error with binding json", zap.Error(err))
+		return
+	}
+
+	album, err := h.service.InsertAlbum(c.Request.Context(), req)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrAlbumExists) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "artist already has an album with this title",
+			})
+			log.Errorw("artist already has an album with this title", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrSongNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song not found",
+			})
+			log.Errorw("song not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with inserting album",
+		})
+		log.Errorw("error with inserting album", zap.Error(err))
+		return
+	}
+	log.Info("album is inserted")
+	c.JSON(http.StatusOK, album)
+}
+
+// @Summary GetAlbums
+// @Tags albums
+// @Description list albums with track counts
+// @ID get albums
+// @Accept json
+// @Produce json
+// @Param artistId query int false "artistId"
+// @Param title query string false "part of the title, case-insensitive"
+// @Param limit query int false "limit"
+// @Param page query int false "page"
+// @Success 200 {object} entities.AlbumsPage
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/albums [get]
+func (h *Handler) GetAlbums(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+
+	result, err := h.service.GetAlbums(c.Request.Context(), c.Query("artistId"), c.Query("title"), c.Query("limit"), c.Query("page"))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting albums",
+		})
+		log.Errorw("error with getting albums", zap.Error(err))
+		return
+	}
+	log.Infow("albums are got")
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary GetAlbum
+// @Tags albums
+// @Description get album with its tracklist ordered by disc and track number
+// @ID get album
+// @Accept json
+// @Produce json
+// @Param id path int true "albumId"
+// @Success 200 {object} entities.Album
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/albums/{id} [get]
+func (h *Handler) GetAlbum(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	albumId := c.Param("id")
+
+	album, err := h.service.GetAlbum(c.Request.Context(), albumId)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrAlbumNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "album not found",
+			})
+			log.Errorw("album not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting album",
+		})
+		log.Errorw("error with getting album", zap.Error(err))
+		return
+	}
+	log.Infow("album is got")
+	c.JSON(http.StatusOK, album)
+}
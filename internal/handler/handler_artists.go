@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary InsertArtist
+// @Tags artists
+// @Description create artist
+// @ID insert artist
+// @Accept json
+// @Produce json
+// @Param input body entities.ArtistRequest true "Artist"
+// @Success 200 {object} entities.Artist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/artists [post]
+func (h *Handler) InsertArtist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	var req entities.ArtistRequest
+
+	err := c.ShouldBindJSON(&req)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with binding json",
+		})
+		log.Errorw("error with binding json", zap.Error(err))
+		return
+	}
+
+	artist, err := h.service.InsertArtist(c.Request.Context(), req)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrArtistExists) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "artist with this name already exists",
+			})
+			log.Errorw("artist with this name already exists", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with inserting artist",
+		})
+		log.Errorw("error with inserting artist", zap.Error(err))
+		return
+	}
+	log.Info("artist is inserted")
+	c.JSON(http.StatusOK, artist)
+}
+
+// @Summary GetArtists
+// @Tags artists
+// @Description list artists with song counts
+// @ID get artists
+// @Accept json
+// @Produce json
+// @Param name query string false "part of the name, case-insensitive"
+// @Param limit query int false "limit"
+// @Param page query int false "page"
+// @Success 200 {object} entities.ArtistsPage
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/artists [get]
+func (h *Handler) GetArtists(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+
+	result, err := h.service.GetArtists(c.Request.Context(), c.Query("name"), c.Query("limit"), c.Query("page"))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting artists",
+		})
+		log.Errorw("error with getting artists", zap.Error(err))
+		return
+	}
+	log.Infow("artists are got")
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary GetArtist
+// @Tags artists
+// @Description get artist with song count
+// @ID get artist
+// @Accept json
+// @Produce json
+// @Param id path int true "artistId"
+// @Success 200 {object} entities.Artist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/artists/{id} [get]
+func (h *Handler) GetArtist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	artistId := c.Param("id")
+
+	artist, err := h.service.GetArtist(c.Request.Context(), artistId)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrArtistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "artist not found",
+			})
+			log.Errorw("artist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting artist",
+		})
+		log.Errorw("error with getting artist", zap.Error(err))
+		return
+	}
+	log.Infow("artist is got")
+	c.JSON(http.StatusOK, artist)
+}
+
+// @Summary RenameArtist
+// @Tags artists
+// @Description rename artist, its songs get the new group name; 409 if an artist has the name or a song would get the name of another song
+// @ID rename artist
+// @Accept json
+// @Produce json
+// @Param id path int true "artistId"
+// @Param input body entities.ArtistRequest true "Artist"
+// @Success 200 {object} entities.Artist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/artists/{id} [patch]
+func (h *Handler) RenameArtist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	artistId := c.Param("id")
+	var req entities.ArtistRequest
+
+	err := c.ShouldBindJSON(&req)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with binding json",
+		})
+		log.Errorw("error with binding json", zap.Error(err))
+		return
+	}
+
+	artist, err := h.service.RenameArtist(c.Request.Context(), artistId, req)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrArtistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "artist not found",
+			})
+			log.Errorw("artist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrArtistExists) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "artist with this name already exists",
+			})
+			log.Errorw("artist with this name already exists", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrSongExists) {
+			songExists(c, err)
+			log.Errorw("song already exists", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with renaming artist",
+		})
+		log.Errorw("error with renaming artist", zap.Error(err))
+		return
+	}
+	log.Infow("artist is renamed")
+	c.JSON(http.StatusOK, artist)
+}
+
+// @Summary DeleteArtist
+// @Tags artists
+// @Description delete artist without songs and albums
+// @ID delete artist
+// @Accept json
+// @Produce json
+// @Param id path int true "artistId"
+// @Success 200 {object} entities.DeleteResponse
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/artists/{id} [delete]
+func (h *Handler) DeleteArtist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	artistId := c.Param("id")
+
+	err := h.service.DeleteArtist(c.Request.Context(), artistId)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrArtistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "artist not found",
+			})
+			log.Errorw("artist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrArtistHasSongs) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "artist has songs",
+			})
+			log.Errorw("artist has songs", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrArtistHasAlbums) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "artist has albums",
+			})
+			log.Errorw("artist has albums", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with deleting artist",
+		})
+		log.Errorw("error with deleting artist", zap.Error(err))
+		return
+	}
+	log.Infow("artist is deleted")
+	c.JSON(http.StatusOK, entities.DeleteResponse{
+		Status: true,
+	})
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary FindDuplicates
+// @Tags songs
+// @Description find clusters of songs that are likely one, best first: two songs are linked when their group and song names are trigram-similar, compared like fuzzy search does, and when both have lyrics the lyrics count as much as the names. Songs linked through each other are one cluster. Merge a cluster with mergesongs
+// @ID find duplicates
+// @Produce json
+// @Param threshold query number false "minimal similarity, 0 to 1, DUPLICATE_THRESHOLD by default"
+// @Param limit query int false "limit"
+// @Param page query int false "page"
+// @Success 200 {object} entities.DuplicatesPage
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/songs/duplicates [get]
+func (h *Handler) FindDuplicates(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+
+	result, err := h.service.FindDuplicates(c.Request.Context(), c.Query("threshold"), c.Query("limit"), c.Query("page"))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with finding duplicate songs",
+		})
+		log.Errorw("error with finding duplicate songs", zap.Error(err))
+		return
+	}
+	log.Infow("duplicate songs are found")
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary MergeSongs
+// @Tags songs
+// @Description merge songs into the song of the path, which is kept as it is: the playlist entries, album tracks and import rows of the merged songs move over to it, and they are deleted with their lyrics and enrichment jobs
+// @ID merge songs
+// @Accept json
+// @Produce json
+// @Param id path int true "songId"
+// @Param input body entities.MergeRequest true "Songs to merge"
+// @Success 200 {object} entities.Song
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/songs/{id}/merge [post]
+func (h *Handler) MergeSongs(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	songId := c.Param("id")
+
+	var req entities.MergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with binding json",
+		})
+		log.Errorw("error with binding json", zap.Error(err))
+		return
+	}
+
+	song, err := h.service.MergeSongs(c.Request.Context(), songId, req)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrSongNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song not found",
+			})
+			log.Errorw("song not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with merging songs",
+		})
+		log.Errorw("error with merging songs", zap.Error(err))
+		return
+	}
+	log.Infow("songs are merged")
+	c.JSON(http.StatusOK, song)
+}
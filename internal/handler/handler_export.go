@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"compress/gzip"
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary ExportSongs
+// @Tags songs
+// @Description export the songs matching getsongs filters as a file streamed from a server-side cursor: csv, ndjson, a json array, or a playlist whose locations are song links (m3u leaves out songs without a link). The response is gzipped if Accept-Encoding allows it
+// @ID export songs
+// @Produce plain
+// @Param format query string false "file format, csv by default" Enums(csv, ndjson, json, m3u, m3u8, xspf)
+// @Param groupName query string false "groupName"
+// @Param artistId query int false "artistId"
+// @Param song query string false "song"
+// @Param releaseDate query string false "exact release date: DD.MM.YYYY, YYYY-MM-DD, or YYYY for the whole year"
+// @Param releasedFrom query string false "released on or after: DD.MM.YYYY, YYYY-MM-DD or YYYY"
+// @Param releasedTo query string false "released on or before: DD.MM.YYYY, YYYY-MM-DD, or YYYY for the end of the year"
+// @Param year query int false "release year"
+// @Param decade query string false "release decade, e.g. 1990 or 1990s"
+// @Param text query string false "text"
+// @Param link query string false "link"
+// @Param sort query string false "comma separated fields, - prefix for descending, as in getsongs"
+// @Param filter query string false "filter expression, as in getsongs"
+// @Success 200 {string} string "song file"
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/songs/export [get]
+func (h *Handler) ExportSongs(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+
+	export, err := h.service.ExportSongs(c.Request.Context(), songFilterParams(c), c.Query("sort"), c.Query("format"))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectFilter) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: err.Error(),
+			})
+			log.Errorw("incorrect filter", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with exporting songs",
+		})
+		log.Errorw("error with exporting songs", zap.Error(err))
+		return
+	}
+
+	// the status is sent with the first bytes, so a failure past this
+	// point can only cut the file short
+	if err := writeSongExport(c, export); err != nil {
+		log.Errorw("error with writing songs export", zap.Error(err))
+		return
+	}
+	log.Infow("songs are exported")
+}
+
+func writeSongExport(c *gin.Context, export entities.SongExport) error {
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.Name}))
+	c.Header("Content-Type", export.ContentType)
+	c.Header("Vary", "Accept-Encoding")
+	// a big export takes longer than the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		return err
+	}
+	if !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+		c.Status(http.StatusOK)
+		return export.Write(c.Writer)
+	}
+	c.Header("Content-Encoding", "gzip")
+	c.Status(http.StatusOK)
+	gz := gzip.NewWriter(c.Writer)
+	if err := export.Write(gz); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// acceptsGzip tells whether an Accept-Encoding header allows gzip with a
+// non-zero quality, by name or else by "*".
+func acceptsGzip(header string) bool {
+	accepts := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "x-gzip" {
+			coding = "gzip"
+		}
+		accepts[coding] = true
+		name, value, ok := strings.Cut(strings.TrimSpace(params), "=")
+		if ok && strings.ToLower(strings.TrimSpace(name)) == "q" {
+			quality, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			accepts[coding] = err == nil && quality > 0
+		}
+	}
+	if accepts, ok := accepts["gzip"]; ok {
+		return accepts
+	}
+	return accepts["*"]
+}
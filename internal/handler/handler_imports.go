@@ -0,0 +1,128 @@
+package handler
+
+import (
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary ImportSongs
+// @Tags songs
+// @Description start a bulk import of a csv or ndjson song file: each row is enriched like insertsong does, unless the file has all its fields, and inserted; songs that exist already are duplicates, so importing a file again changes nothing. Follow the import and its per-row report with getimport
+// @ID import songs
+// @Accept plain
+// @Produce json
+// @Param format query string false "file format, guessed from the file by default" Enums(csv, ndjson)
+// @Param input body string true "song file: csv with group, song and optional releaseDate, text, link, album, albumReleaseDate, albumCover, disc, track columns, or one song json per line, IMPORT_MAX_MB at most"
+// @Success 202 {object} entities.SongImport
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 413 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/songs/import [post]
+func (h *Handler) ImportSongs(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+
+	data, ok := readBody(c, h.importMaxBytes)
+	if !ok {
+		return
+	}
+
+	songImport, err := h.service.StartImport(c.Request.Context(), c.Query("format"), data)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectSongFile) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: err.Error(),
+			})
+			log.Errorw("incorrect song file", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with importing songs",
+		})
+		log.Errorw("error with importing songs", zap.Error(err))
+		return
+	}
+	log.Infow("song import is started")
+	c.JSON(http.StatusAccepted, songImport)
+}
+
+// @Summary GetImport
+// @Tags songs
+// @Description get a song import: its status, counters and a page of its per-row report in file order
+// @ID get import
+// @Produce json
+// @Param id path int true "importId"
+// @Param status query string false "only rows of the status" Enums(inserted, duplicate, enrichment_failed, invalid)
+// @Param limit query int false "limit"
+// @Param page query int false "page"
+// @Success 200 {object} entities.SongImport
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/songs/imports/{id} [get]
+func (h *Handler) GetImport(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	importId := c.Param("id")
+
+	songImport, err := h.service.GetImport(c.Request.Context(), importId, c.Query("status"), c.Query("limit"), c.Query("page"))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrImportNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song import not found",
+			})
+			log.Errorw("song import not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting song import",
+		})
+		log.Errorw("error with getting song import", zap.Error(err))
+		return
+	}
+	log.Infow("song import is got")
+	c.JSON(http.StatusOK, songImport)
+}
+
+// readBody reads the request body, limit bytes at most. It answers the
+// request itself when that fails, with 413 for a larger body, and returns
+// false then.
+func readBody(c *gin.Context, limit int64) ([]byte, bool) {
+	log := logger.LoggerFromContext(c)
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, projectError.ErrorMessage{
+			Error: fmt.Sprintf("body is larger than %d bytes", limit),
+		})
+		log.Errorw("body is too large", zap.Error(err))
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with reading body",
+		})
+		log.Errorw("error with reading body", zap.Error(err))
+		return nil, false
+	}
+	return data, true
+}
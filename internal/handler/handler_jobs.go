@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary GetJob
+// @Tags jobs
+// @Description get enrichment job status
+// @ID get job
+// @Accept json
+// @Produce json
+// @Param id path int true "jobId"
+// @Success 200 {object} entities.EnrichmentJob
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/jobs/{id} [get]
+func (h *Handler) GetJob(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	jobId := c.Param("id")
+
+	job, err := h.service.GetJob(c.Request.Context(), jobId)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "job not found",
+			})
+			log.Errorw("job not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting job",
+		})
+		log.Errorw("error with getting job", zap.Error(err))
+		return
+	}
+	log.Infow("job is got")
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary RetryJob
+// @Tags jobs
+// @Description requeue a dead enrichment job
+// @ID retry job
+// @Accept json
+// @Produce json
+// @Param id path int true "jobId"
+// @Success 200 {object} entities.RetryJobResponse
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/jobs/{id}/retry [post]
+func (h *Handler) RetryJob(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	jobId := c.Param("id")
+
+	err := h.service.RetryJob(c.Request.Context(), jobId)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "job not found",
+			})
+			log.Errorw("job not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrJobNotRetryable) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "job is not in dead state",
+			})
+			log.Errorw("job is not in dead state", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with retrying job",
+		})
+		log.Errorw("error with retrying job", zap.Error(err))
+		return
+	}
+	log.Infow("job is queued again")
+	c.JSON(http.StatusOK, entities.RetryJobResponse{
+		ID:     jobId,
+		Status: entities.JobStatusQueued,
+	})
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary GetSections
+// @Tags lyrics
+// @Description get lyrics split into verse/chorus/bridge/intro/outro sections
+// @ID get sections
+// @Accept json
+// @Produce json
+// @Param id path int true "songId"
+// @Param kind query string false "section kind" Enums(verse, chorus, bridge, intro, outro)
+// @Param number query int false "section number within its kind"
+// @Success 200 {object} entities.SectionsResponse
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/songs/{id}/sections [get]
+func (h *Handler) GetSections(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	songId := c.Param("id")
+	kind := c.Query("kind")
+	number := c.Query("number")
+
+	sections, err := h.service.GetSections(c.Request.Context(), songId, kind, number)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrSongNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song not found",
+			})
+			log.Errorw("song not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting sections",
+		})
+		log.Errorw("error with getting sections", zap.Error(err))
+		return
+	}
+	log.Infow("sections are got")
+	c.JSON(http.StatusOK, entities.SectionsResponse{
+		Sections: sections,
+	})
+}
+
+// @Summary ImportLRC
+// @Tags lyrics
+// @Description replace song text with synced lyrics in LRC format
+// @ID import lrc
+// @Accept plain
+// @Produce json
+// @Param id path int true "songId"
+// @Param input body string true "LRC file"
+// @Success 200 {object} entities.UpdateResponse
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/songs/{id}/lrc [put]
+func (h *Handler) ImportLRC(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	songId := c.Param("id")
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with reading body",
+		})
+		log.Errorw("error with reading body", zap.Error(err))
+		return
+	}
+
+	err = h.service.ImportLRC(c.Request.Context(), songId, string(data))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrSongNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song not found",
+			})
+			log.Errorw("song not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with importing lrc",
+		})
+		log.Errorw("error with importing lrc", zap.Error(err))
+		return
+	}
+	log.Infow("lrc is imported")
+	c.JSON(http.StatusOK, entities.UpdateResponse{
+		ID: songId,
+	})
+}
+
+// @Summary ExportLRC
+// @Tags lyrics
+// @Description export synced lyrics in LRC format
+// @ID export lrc
+// @Produce plain
+// @Param id path int true "songId"
+// @Success 200 {string} string "LRC file"
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/songs/{id}/lrc [get]
+func (h *Handler) ExportLRC(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	songId := c.Param("id")
+
+	lrc, err := h.service.ExportLRC(c.Request.Context(), songId)
+
+	if err != nil {
+		h.syncedLyricsError(c, err)
+		return
+	}
+	log.Infow("lrc is exported")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.lrc"`, songId))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(lrc))
+}
+
+// @Summary GetActiveLine
+// @Tags lyrics
+// @Description get the synced lyrics line active at a playback position
+// @ID get active line
+// @Accept json
+// @Produce json
+// @Param id path int true "songId"
+// @Param position query string true "seconds (83.5) or mm:ss.xx (01:23.50)"
+// @Success 200 {object} entities.ActiveLineResponse
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/songs/{id}/lyrics/at [get]
+func (h *Handler) GetActiveLine(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	songId := c.Param("id")
+	position := c.Query("position")
+
+	line, err := h.service.GetActiveLine(c.Request.Context(), songId, position)
+
+	if err != nil {
+		h.syncedLyricsError(c, err)
+		return
+	}
+	log.Infow("active line is got")
+	c.JSON(http.StatusOK, line)
+}
+
+func (h *Handler) syncedLyricsError(c *gin.Context, err error) {
+	log := logger.LoggerFromContext(c)
+	if errors.Is(err, projectError.ErrSongNotFound) {
+		c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+			Error: "song not found",
+		})
+		log.Errorw("song not found", zap.Error(err))
+		return
+	} else if errors.Is(err, projectError.ErrNoSyncedLyrics) {
+		c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+			Error: "song has no synced lyrics",
+		})
+		log.Errorw("song has no synced lyrics", zap.Error(err))
+		return
+	} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "incorrect request",
+		})
+		log.Errorw("incorrect request", zap.Error(err))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+		Error: "error with getting synced lyrics",
+	})
+	log.Errorw("error with getting synced lyrics", zap.Error(err))
+}
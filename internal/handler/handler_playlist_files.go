@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary ExportPlaylist
+// @Tags playlists
+// @Description export playlist as a playlist file, song links are the locations; m3u leaves out songs without a link
+// @ID export playlist
+// @Produce plain
+// @Param id path int true "playlistId"
+// @Param format query string false "playlist format, m3u8 by default" Enums(m3u, m3u8, xspf)
+// @Success 200 {string} string "playlist file"
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists/{id}/export [get]
+func (h *Handler) ExportPlaylist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	playlistId := c.Param("id")
+
+	file, err := h.service.ExportPlaylist(c.Request.Context(), playlistId, c.Query("format"))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrPlaylistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist not found",
+			})
+			log.Errorw("playlist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with exporting playlist",
+		})
+		log.Errorw("error with exporting playlist", zap.Error(err))
+		return
+	}
+	log.Infow("playlist is exported")
+	writePlaylistFile(c, file)
+}
+
+// @Summary ImportPlaylist
+// @Tags playlists
+// @Description create a playlist from an m3u, m3u8 or xspf file; tracks are matched to songs by group and song name, missing songs are inserted like insertsong does
+// @ID import playlist
+// @Accept plain
+// @Produce json
+// @Param format query string false "playlist format, guessed from the file by default" Enums(m3u, m3u8, xspf)
+// @Param name query string false "playlist name, the file title by default"
+// @Param input body string true "playlist file, PLAYLIST_IMPORT_MAX_MB at most"
+// @Success 200 {object} entities.PlaylistImportResponse
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 413 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists/import [post]
+func (h *Handler) ImportPlaylist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+
+	data, ok := readBody(c, h.playlistImportMaxBytes)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.ImportPlaylist(c.Request.Context(), c.Query("name"), c.Query("format"), data)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectFile) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: err.Error(),
+			})
+			log.Errorw("incorrect playlist file", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with importing playlist",
+		})
+		log.Errorw("error with importing playlist", zap.Error(err))
+		return
+	}
+	log.Infow("playlist is imported")
+	c.JSON(http.StatusOK, result)
+}
+
+func writePlaylistFile(c *gin.Context, file entities.PlaylistFile) {
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
@@ -0,0 +1,508 @@
+package handler
+
+import (
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary InsertPlaylist
+// @Tags playlists
+// @Description create playlist
+// @ID insert playlist
+// @Accept json
+// @Produce json
+// @Param input body entities.PlaylistRequest true "Playlist"
+// @Success 200 {object} entities.Playlist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists [post]
+func (h *Handler) InsertPlaylist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	var req entities.PlaylistRequest
+
+	err := c.ShouldBindJSON(&req)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with binding json",
+		})
+		log.Errorw("error with binding json", zap.Error(err))
+		return
+	}
+
+	playlist, err := h.service.InsertPlaylist(c.Request.Context(), req)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with inserting playlist",
+		})
+		log.Errorw("error with inserting playlist", zap.Error(err))
+		return
+	}
+	log.Infow("playlist is inserted")
+	c.JSON(http.StatusOK, playlist)
+}
+
+// @Summary GetPlaylists
+// @Tags playlists
+// @Description list playlists without entries
+// @ID get playlists
+// @Accept json
+// @Produce json
+// @Param limit query int false "limit"
+// @Param page query int false "page"
+// @Success 200 {object} entities.PlaylistsPage
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists [get]
+func (h *Handler) GetPlaylists(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+
+	result, err := h.service.GetPlaylists(c.Request.Context(), c.Query("limit"), c.Query("page"))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting playlists",
+		})
+		log.Errorw("error with getting playlists", zap.Error(err))
+		return
+	}
+	log.Infow("playlists are got")
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary GetPlaylist
+// @Tags playlists
+// @Description get playlist with its entries in order
+// @ID get playlist
+// @Accept json
+// @Produce json
+// @Param id path int true "playlistId"
+// @Success 200 {object} entities.Playlist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists/{id} [get]
+func (h *Handler) GetPlaylist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	playlistId := c.Param("id")
+
+	playlist, err := h.service.GetPlaylist(c.Request.Context(), playlistId)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrPlaylistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist not found",
+			})
+			log.Errorw("playlist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting playlist",
+		})
+		log.Errorw("error with getting playlist", zap.Error(err))
+		return
+	}
+	log.Infow("playlist is got")
+	c.JSON(http.StatusOK, playlist)
+}
+
+// @Summary UpdatePlaylist
+// @Tags playlists
+// @Description update playlist name or description
+// @ID update playlist
+// @Accept json
+// @Produce json
+// @Param id path int true "playlistId"
+// @Param input body entities.PlaylistUpdate true "Playlist"
+// @Success 200 {object} entities.Playlist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists/{id} [patch]
+func (h *Handler) UpdatePlaylist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	playlistId := c.Param("id")
+	var req entities.PlaylistUpdate
+
+	err := c.ShouldBindJSON(&req)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with binding json",
+		})
+		log.Errorw("error with binding json", zap.Error(err))
+		return
+	}
+
+	playlist, err := h.service.UpdatePlaylist(c.Request.Context(), playlistId, req)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrPlaylistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist not found",
+			})
+			log.Errorw("playlist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrPlaylistChanged) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "playlist was changed, reload it",
+			})
+			log.Errorw("playlist was changed, reload it", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with updating playlist",
+		})
+		log.Errorw("error with updating playlist", zap.Error(err))
+		return
+	}
+	log.Infow("playlist is updated")
+	c.JSON(http.StatusOK, playlist)
+}
+
+// @Summary DeletePlaylist
+// @Tags playlists
+// @Description delete playlist
+// @ID delete playlist
+// @Accept json
+// @Produce json
+// @Param id path int true "playlistId"
+// @Param version query int false "current version of the playlist, 409 if it is not"
+// @Success 200 {object} entities.DeleteResponse
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists/{id} [delete]
+func (h *Handler) DeletePlaylist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	playlistId := c.Param("id")
+
+	err := h.service.DeletePlaylist(c.Request.Context(), playlistId, c.Query("version"))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrPlaylistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist not found",
+			})
+			log.Errorw("playlist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrPlaylistChanged) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "playlist was changed, reload it",
+			})
+			log.Errorw("playlist was changed, reload it", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with deleting playlist",
+		})
+		log.Errorw("error with deleting playlist", zap.Error(err))
+		return
+	}
+	log.Infow("playlist is deleted")
+	c.JSON(http.StatusOK, entities.DeleteResponse{
+		Status: true,
+	})
+}
+
+// @Summary AddPlaylistEntry
+// @Tags playlists
+// @Description add song to playlist at position, the entries from there move down; without position the song is appended
+// @ID add playlist entry
+// @Accept json
+// @Produce json
+// @Param id path int true "playlistId"
+// @Param input body entities.PlaylistEntryRequest true "Entry"
+// @Success 200 {object} entities.Playlist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists/{id}/entries [post]
+func (h *Handler) AddPlaylistEntry(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	playlistId := c.Param("id")
+	var req entities.PlaylistEntryRequest
+
+	err := c.ShouldBindJSON(&req)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with binding json",
+		})
+		log.Errorw("error with binding json", zap.Error(err))
+		return
+	}
+
+	playlist, err := h.service.AddEntry(c.Request.Context(), playlistId, req)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrPlaylistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist not found",
+			})
+			log.Errorw("playlist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrSongNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song not found",
+			})
+			log.Errorw("song not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrPlaylistChanged) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "playlist was changed, reload it",
+			})
+			log.Errorw("playlist was changed, reload it", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with adding playlist entry",
+		})
+		log.Errorw("error with adding playlist entry", zap.Error(err))
+		return
+	}
+	log.Infow("playlist entry is added")
+	c.JSON(http.StatusOK, playlist)
+}
+
+// @Summary MovePlaylistEntry
+// @Tags playlists
+// @Description move playlist entry to position
+// @ID move playlist entry
+// @Accept json
+// @Produce json
+// @Param id path int true "playlistId"
+// @Param entryId path int true "entryId"
+// @Param input body entities.PlaylistMoveRequest true "Position"
+// @Success 200 {object} entities.Playlist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists/{id}/entries/{entryId}/move [post]
+func (h *Handler) MovePlaylistEntry(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	playlistId := c.Param("id")
+	entryId := c.Param("entryId")
+	var req entities.PlaylistMoveRequest
+
+	err := c.ShouldBindJSON(&req)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with binding json",
+		})
+		log.Errorw("error with binding json", zap.Error(err))
+		return
+	}
+
+	playlist, err := h.service.MoveEntry(c.Request.Context(), playlistId, entryId, req)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrPlaylistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist not found",
+			})
+			log.Errorw("playlist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrEntryNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist entry not found",
+			})
+			log.Errorw("playlist entry not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrPlaylistChanged) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "playlist was changed, reload it",
+			})
+			log.Errorw("playlist was changed, reload it", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with moving playlist entry",
+		})
+		log.Errorw("error with moving playlist entry", zap.Error(err))
+		return
+	}
+	log.Infow("playlist entry is moved")
+	c.JSON(http.StatusOK, playlist)
+}
+
+// @Summary RemovePlaylistEntry
+// @Tags playlists
+// @Description remove entry from playlist
+// @ID remove playlist entry
+// @Accept json
+// @Produce json
+// @Param id path int true "playlistId"
+// @Param entryId path int true "entryId"
+// @Param version query int false "current version of the playlist, 409 if it is not"
+// @Success 200 {object} entities.Playlist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists/{id}/entries/{entryId} [delete]
+func (h *Handler) RemovePlaylistEntry(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	playlistId := c.Param("id")
+	entryId := c.Param("entryId")
+
+	playlist, err := h.service.RemoveEntry(c.Request.Context(), playlistId, entryId, c.Query("version"))
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrPlaylistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist not found",
+			})
+			log.Errorw("playlist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrEntryNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist entry not found",
+			})
+			log.Errorw("playlist entry not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrPlaylistChanged) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "playlist was changed, reload it",
+			})
+			log.Errorw("playlist was changed, reload it", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with removing playlist entry",
+		})
+		log.Errorw("error with removing playlist entry", zap.Error(err))
+		return
+	}
+	log.Infow("playlist entry is removed")
+	c.JSON(http.StatusOK, playlist)
+}
+
+// @Summary ReorderPlaylist
+// @Tags playlists
+// @Description put playlist entries in the given order, entryIds must list every entry once
+// @ID reorder playlist
+// @Accept json
+// @Produce json
+// @Param id path int true "playlistId"
+// @Param input body entities.PlaylistOrderRequest true "Order"
+// @Success 200 {object} entities.Playlist
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/playlists/{id}/order [put]
+func (h *Handler) ReorderPlaylist(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	playlistId := c.Param("id")
+	var req entities.PlaylistOrderRequest
+
+	err := c.ShouldBindJSON(&req)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "error with binding json",
+		})
+		log.Errorw("error with binding json", zap.Error(err))
+		return
+	}
+
+	playlist, err := h.service.ReorderPlaylist(c.Request.Context(), playlistId, req)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrPlaylistNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "playlist not found",
+			})
+			log.Errorw("playlist not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrEntriesMismatch) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "entries do not match the playlist",
+			})
+			log.Errorw("entries do not match the playlist", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrPlaylistChanged) {
+			c.JSON(http.StatusConflict, projectError.ErrorMessage{
+				Error: "playlist was changed, reload it",
+			})
+			log.Errorw("playlist was changed, reload it", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with reordering playlist",
+		})
+		log.Errorw("error with reordering playlist", zap.Error(err))
+		return
+	}
+	log.Infow("playlist is reordered")
+	c.JSON(http.StatusOK, playlist)
+}
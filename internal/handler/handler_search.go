@@ -0,0 +1,115 @@
+package handler
+
+import (
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// @Summary SearchLyrics
+// @Tags search
+// @Description full-text search over song titles and lyrics, ranked, with highlighted fragments
+// @ID search lyrics
+// @Accept json
+// @Produce json
+// @Param q query string true "search query, websearch syntax: words, \"phrase\", or, -word"
+// @Param lang query string false "auto (default), english or russian" Enums(auto, english, russian)
+// @Param limit query int false "limit"
+// @Param page query int false "page"
+// @Param format query string false "array returns the legacy bare array of results" Enums(envelope, array)
+// @Success 200 {object} entities.SearchPage "format=array returns []entities.SearchResult"
+// @Header 200 {string} Link "RFC 8288 links to the first, prev, next and last pages"
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/search [get]
+func (h *Handler) SearchLyrics(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	query := c.Query("q")
+	lang := c.Query("lang")
+	limit := c.Query("limit")
+	page := c.Query("page")
+
+	result, err := h.service.SearchLyrics(c.Request.Context(), query, lang, limit, page)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with searching songs",
+		})
+		log.Errorw("error with searching songs", zap.Error(err))
+		return
+	}
+	log.Infow("songs are searched")
+	links := pageNumberLinks(c.Request.URL, result.Page, result.Limit, &result.Total, len(result.Items))
+	result.Next = links["next"]
+	result.Prev = links["prev"]
+	writeLinkHeader(c, links)
+	if c.Query("format") == formatArray {
+		c.JSON(http.StatusOK, result.Items)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary FuzzySearch
+// @Tags search
+// @Description typo-tolerant, case and accent insensitive search by group and song name, ordered by similarity
+// @ID fuzzy search
+// @Accept json
+// @Produce json
+// @Param q query string true "search query"
+// @Param field query string false "all (default), group or song" Enums(all, group, song)
+// @Param threshold query number false "minimal similarity from 0 to 1, FUZZY_THRESHOLD by default"
+// @Param limit query int false "limit"
+// @Param page query int false "page"
+// @Param format query string false "array returns the legacy bare array of results" Enums(envelope, array)
+// @Success 200 {object} entities.FuzzyPage "format=array returns []entities.FuzzyResult"
+// @Header 200 {string} Link "RFC 8288 links to the first, prev, next and last pages"
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Router /api/search/fuzzy [get]
+func (h *Handler) FuzzySearch(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	query := c.Query("q")
+	field := c.Query("field")
+	threshold := c.Query("threshold")
+	limit := c.Query("limit")
+	page := c.Query("page")
+
+	result, err := h.service.FuzzySearch(c.Request.Context(), query, field, threshold, limit, page)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with searching songs",
+		})
+		log.Errorw("error with searching songs", zap.Error(err))
+		return
+	}
+	log.Infow("songs are searched")
+	links := pageNumberLinks(c.Request.URL, result.Page, result.Limit, &result.Total, len(result.Items))
+	result.Next = links["next"]
+	result.Prev = links["prev"]
+	writeLinkHeader(c, links)
+	if c.Query("format") == formatArray {
+		c.JSON(http.StatusOK, result.Items)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -4,6 +4,7 @@ import (
 	"effectiveMobile/internal/entities"
 	projectError "effectiveMobile/internal/errors"
 	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/service"
 	"errors"
 	"net/http"
 
@@ -19,8 +20,12 @@ import (
 // @Produce json
 // @Param input body entities.SongRequest true "Song"
 // @Success 200 {object} entities.InsertResponse
+// @Success 202 {object} entities.InsertResponse
 // @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.SongExistsMessage
 // @Failure 500 {object} errors.ErrorMessage
+// @Failure 503 {object} errors.ErrorMessage
 // @Router /api/insertsong [post]
 func (h *Handler) InsertSong(c *gin.Context) {
 	log := logger.LoggerFromContext(c)
@@ -36,10 +41,26 @@ func (h *Handler) InsertSong(c *gin.Context) {
 		return
 	}
 
-	id, err := h.service.InsertSong(c.Request.Context(), req)
+	result, err := h.service.InsertSong(c.Request.Context(), req)
 
 	if err != nil {
-		if errors.Is(err, projectError.ErrAnotherStatucCode) {
+		if errors.Is(err, projectError.ErrSongExists) {
+			songExists(c, err)
+			log.Errorw("song already exists", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrSongInfoNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song info not found",
+			})
+			log.Errorw("song info not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrSongApiUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, projectError.ErrorMessage{
+				Error: "song api is unavailable",
+			})
+			log.Errorw("song api is unavailable", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrAnotherStatucCode) {
 			c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
 				Error: "error with sending request",
 			})
@@ -52,10 +73,26 @@ func (h *Handler) InsertSong(c *gin.Context) {
 		log.Errorw("error with inserting song", zap.Error(err))
 		return
 	}
+	if result.JobID != 0 {
+		log.Info("song is inserted, enrichment is queued")
+		c.JSON(http.StatusAccepted, result)
+		return
+	}
 	log.Info("song is inserted")
-	c.JSON(http.StatusOK, entities.InsertResponse{
-		ID: id,
-	})
+	c.JSON(http.StatusOK, result)
+}
+
+// songExists answers 409 with the id of the song that has the name, when it
+// is known.
+func songExists(c *gin.Context, err error) {
+	message := projectError.SongExistsMessage{
+		Error: "song already exists",
+	}
+	var exists *projectError.SongExistsError
+	if errors.As(err, &exists) {
+		message.ID = exists.ID
+	}
+	c.JSON(http.StatusConflict, message)
 }
 
 // @Summary GetSongs
@@ -67,34 +104,53 @@ func (h *Handler) InsertSong(c *gin.Context) {
 // @Param limit query int false "limit"
 // @Param page query int false   "page"
 // @Param groupName query string false "groupName"
+// @Param artistId query int false "artistId"
 // @Param song query string false "song"
-// @Param releaseDate query string false "releaseDate"
+// @Param releaseDate query string false "exact release date: DD.MM.YYYY, YYYY-MM-DD, or YYYY for the whole year"
+// @Param releasedFrom query string false "released on or after: DD.MM.YYYY, YYYY-MM-DD or YYYY"
+// @Param releasedTo query string false "released on or before: DD.MM.YYYY, YYYY-MM-DD, or YYYY for the end of the year"
+// @Param year query int false "release year"
+// @Param decade query string false "release decade, e.g. 1990 or 1990s"
 // @Param text query string false "text"
 // @Param link query string false "link"
-// @Success 200 {object} []entities.Song
+// @Param sort query string false "comma separated fields, - prefix for descending, e.g. releaseDate,-group,song; fields: group, song, releaseDate, id"
+// @Param filter query string false "filter expression, e.g. group eq \"Muse\" and (year ge 2000 or text contains \"love\"); see README"
+// @Param mode query string false "page (default) or cursor" Enums(page, cursor)
+// @Param cursor query string false "nextCursor or prevCursor of a previous page, implies mode=cursor"
+// @Param count query string false "how to compute total: exact (default in page mode), estimate or none (default in cursor mode)" Enums(exact, estimate, none)
+// @Param format query string false "array returns the legacy bare array of songs" Enums(envelope, array)
+// @Success 200 {object} entities.SongsPage "format=array returns []entities.Song"
+// @Header 200 {string} Link "RFC 8288 links to the first, prev, next and last pages"
 // @Failure 400 {object} errors.ErrorMessage
 // @Failure 404 {object} errors.ErrorMessage
 // @Failure 500 {object} errors.ErrorMessage
 // @Router /api/getsongs [get]
 func (h *Handler) GetSongs(c *gin.Context) {
 	log := logger.LoggerFromContext(c)
-	var filters entities.Song
+	filters := songFilterParams(c)
 
 	limit := c.Query("limit")
 	page := c.Query("page")
-	groupName := c.Query("groupName")
-	song := c.Query("song")
-	releaseDate := c.Query("releaseDate")
-	text := c.Query("text")
-	link := c.Query("link")
 
-	filters.GroupName = groupName
-	filters.Song = song
-	filters.ReleaseDate = releaseDate
-	filters.Text = text
-	filters.Link = link
+	mode := c.Query("mode")
+	cursor := c.Query("cursor")
+	count := c.Query("count")
+	sort := c.Query("sort")
+	if c.Query("format") == formatArray && count == "" {
+		count = service.CountNone
+	}
+	if mode == "cursor" || cursor != "" {
+		h.getSongsPage(c, filters, sort, limit, cursor, count)
+		return
+	} else if mode != "" && mode != "page" {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "incorrect request",
+		})
+		log.Errorw("incorrect request", "mode", mode)
+		return
+	}
 
-	result, err := h.service.GetSongs(c.Request.Context(), filters, limit, page)
+	result, err := h.service.GetSongs(c.Request.Context(), filters, sort, limit, page, count)
 
 	if err != nil {
 		if errors.Is(err, projectError.ErrSongNotFound) {
@@ -103,6 +159,12 @@ func (h *Handler) GetSongs(c *gin.Context) {
 			})
 			log.Errorw("song not found", zap.Error(err))
 			return
+		} else if errors.Is(err, projectError.ErrIncorrectFilter) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: err.Error(),
+			})
+			log.Errorw("incorrect filter", zap.Error(err))
+			return
 		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
 			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
 				Error: "incorrect request",
@@ -117,7 +179,53 @@ func (h *Handler) GetSongs(c *gin.Context) {
 		return
 	}
 	log.Infow("songs are got")
-	c.JSON(http.StatusOK, result)
+	writeSongsPage(c, result)
+}
+
+// songFilterParams reads the song filters shared by getsongs and the song
+// export.
+func songFilterParams(c *gin.Context) entities.SongFilterParams {
+	return entities.SongFilterParams{
+		GroupName:    c.Query("groupName"),
+		Song:         c.Query("song"),
+		ReleaseDate:  c.Query("releaseDate"),
+		ReleasedFrom: c.Query("releasedFrom"),
+		ReleasedTo:   c.Query("releasedTo"),
+		Year:         c.Query("year"),
+		Decade:       c.Query("decade"),
+		ArtistID:     c.Query("artistId"),
+		Text:         c.Query("text"),
+		Link:         c.Query("link"),
+		Filter:       c.Query("filter"),
+	}
+}
+
+func (h *Handler) getSongsPage(c *gin.Context, filters entities.SongFilterParams, sort, limit, cursor, count string) {
+	log := logger.LoggerFromContext(c)
+	result, err := h.service.GetSongsPage(c.Request.Context(), filters, sort, limit, cursor, count)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrIncorrectFilter) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: err.Error(),
+			})
+			log.Errorw("incorrect filter", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting songs",
+		})
+		log.Errorw("error with getting songs", zap.Error(err))
+		return
+	}
+	log.Infow("songs page is got")
+	writeSongsPage(c, result)
 }
 
 // @Summary DeleteSong
@@ -175,7 +283,8 @@ func (h *Handler) DeleteSong(c *gin.Context) {
 // @Param page query int false "page"
 // @Param limit query int false "limit"
 // @Param lineInVerse query int false "lineInVerse"
-// @Success 200 {object} entities.TextResponse
+// @Param mode query string false "lines (default) or stanza" Enums(lines, stanza)
+// @Success 200 {object} entities.TextResponse "mode=lines; mode=stanza returns entities.VersesResponse"
 // @Failure 400 {object} errors.ErrorMessage
 // @Failure 404 {object} errors.ErrorMessage
 // @Failure 500 {object} errors.ErrorMessage
@@ -194,6 +303,17 @@ func (h *Handler) GetTextSong(c *gin.Context) {
 	lineInVerse := c.Query("lineInVerse")
 	page := c.Query("page")
 	limit := c.Query("limit")
+	mode := c.Query("mode")
+	if mode == "stanza" {
+		h.getVerses(c, page, limit, songId)
+		return
+	} else if mode != "" && mode != "lines" {
+		c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+			Error: "incorrect request",
+		})
+		log.Errorw("incorrect request", "mode", mode)
+		return
+	}
 	text, err := h.service.GetTextSong(c.Request.Context(), lineInVerse, page, limit, songId)
 
 	if err != nil {
@@ -222,6 +342,34 @@ func (h *Handler) GetTextSong(c *gin.Context) {
 	})
 }
 
+func (h *Handler) getVerses(c *gin.Context, page, limit, songId string) {
+	log := logger.LoggerFromContext(c)
+	verses, err := h.service.GetVerses(c.Request.Context(), page, limit, songId)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrSongNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song not found",
+			})
+			log.Errorw("song not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with getting song",
+		})
+		log.Errorw("error with getting song", zap.Error(err))
+		return
+	}
+	log.Infow("song verses are got")
+	c.JSON(http.StatusOK, verses)
+}
+
 // @Summary UpdateSong
 // @Tags songs
 // @Description update song
@@ -233,6 +381,7 @@ func (h *Handler) GetTextSong(c *gin.Context) {
 // @Success 200 {object} entities.TextResponse
 // @Failure 400 {object} errors.ErrorMessage
 // @Failure 404 {object} errors.ErrorMessage
+// @Failure 409 {object} errors.SongExistsMessage
 // @Failure 500 {object} errors.ErrorMessage
 // @Router /api/updatesong/{id} [patch]
 func (h *Handler) UpdateSong(c *gin.Context) {
@@ -265,6 +414,10 @@ func (h *Handler) UpdateSong(c *gin.Context) {
 			})
 			log.Errorw("song not found", zap.Error(err))
 			return
+		} else if errors.Is(err, projectError.ErrSongExists) {
+			songExists(c, err)
+			log.Errorw("song already exists", zap.Error(err))
+			return
 		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
 			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
 				Error: "incorrect request",
@@ -284,3 +437,58 @@ func (h *Handler) UpdateSong(c *gin.Context) {
 		ID: songId,
 	})
 }
+
+// @Summary RefreshSong
+// @Tags songs
+// @Description re-query song info and merge it, keeping fields edited by hand
+// @ID refresh song
+// @Accept json
+// @Produce json
+// @Param id path int true "songId"
+// @Success 200 {object} entities.Song
+// @Failure 400 {object} errors.ErrorMessage
+// @Failure 404 {object} errors.ErrorMessage
+// @Failure 500 {object} errors.ErrorMessage
+// @Failure 503 {object} errors.ErrorMessage
+// @Router /api/songs/{id}/refresh [post]
+func (h *Handler) RefreshSong(c *gin.Context) {
+	log := logger.LoggerFromContext(c)
+	songId := c.Param("id")
+
+	song, err := h.service.RefreshSong(c.Request.Context(), songId)
+
+	if err != nil {
+		if errors.Is(err, projectError.ErrSongNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song not found",
+			})
+			log.Errorw("song not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrSongInfoNotFound) {
+			c.JSON(http.StatusNotFound, projectError.ErrorMessage{
+				Error: "song info not found",
+			})
+			log.Errorw("song info not found", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrSongApiUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, projectError.ErrorMessage{
+				Error: "song api is unavailable",
+			})
+			log.Errorw("song api is unavailable", zap.Error(err))
+			return
+		} else if errors.Is(err, projectError.ErrIncorrectRequest) {
+			c.JSON(http.StatusBadRequest, projectError.ErrorMessage{
+				Error: "incorrect request",
+			})
+			log.Errorw("incorrect request", zap.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, projectError.ErrorMessage{
+			Error: "error with refreshing song",
+		})
+		log.Errorw("error with refreshing song", zap.Error(err))
+		return
+	}
+	log.Infow("song is refreshed")
+	c.JSON(http.StatusOK, song)
+}
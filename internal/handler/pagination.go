@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"effectiveMobile/internal/entities"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// formatArray is the format query param value that keeps the legacy bare
+// array response of list endpoints.
+const formatArray = "array"
+
+// writeSongsPage sets the neighbour page links of page, both in the body
+// and as an RFC 8288 Link header, and writes the envelope, or only its items
+// when the legacy array format is requested.
+func writeSongsPage(c *gin.Context, page entities.SongsPage) {
+	var links map[string]string
+	if page.Page > 0 {
+		// without an exact total a full page is the only hint that more follow
+		var total *int64
+		if !page.TotalEstimated {
+			total = page.Total
+		}
+		links = pageNumberLinks(c.Request.URL, page.Page, page.Limit, total, len(page.Items))
+	} else {
+		links = make(map[string]string)
+		if page.NextCursor != "" {
+			links["next"] = pageLink(c.Request.URL, "cursor", page.NextCursor)
+		}
+		if page.PrevCursor != "" {
+			links["prev"] = pageLink(c.Request.URL, "cursor", page.PrevCursor)
+		}
+	}
+	page.Next = links["next"]
+	page.Prev = links["prev"]
+	writeLinkHeader(c, links)
+
+	if c.Query("format") == formatArray {
+		c.JSON(http.StatusOK, page.Items)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// pageNumberLinks returns the first, prev, next and last page links of a
+// page of count items. total is the exact number of items, or nil when it
+// is unknown; then a full page is taken to have a next one.
+func pageNumberLinks(requestUrl *url.URL, page, limit int, total *int64, count int) map[string]string {
+	links := make(map[string]string)
+	if page > 1 {
+		links["prev"] = pageLink(requestUrl, "page", strconv.Itoa(page-1))
+	}
+	if (total != nil && int64(page)*int64(limit) < *total) || (total == nil && count == limit) {
+		links["next"] = pageLink(requestUrl, "page", strconv.Itoa(page+1))
+	}
+	links["first"] = pageLink(requestUrl, "page", "1")
+	if total != nil && *total > 0 {
+		last := (*total + int64(limit) - 1) / int64(limit)
+		links["last"] = pageLink(requestUrl, "page", strconv.FormatInt(last, 10))
+	}
+	return links
+}
+
+// writeLinkHeader sets the RFC 8288 Link header to links, keyed by rel.
+func writeLinkHeader(c *gin.Context, links map[string]string) {
+	header := make([]string, 0, len(links))
+	for _, rel := range []string{"first", "prev", "next", "last"} {
+		if link, ok := links[rel]; ok {
+			header = append(header, fmt.Sprintf("<%s>; rel=%q", link, rel))
+		}
+	}
+	if len(header) > 0 {
+		c.Header("Link", strings.Join(header, ", "))
+	}
+}
+
+// pageLink returns the request path and query with param set to value.
+func pageLink(requestUrl *url.URL, param, value string) string {
+	query := requestUrl.Query()
+	query.Set(param, value)
+	link := url.URL{Path: requestUrl.Path, RawQuery: query.Encode()}
+	return link.String()
+}
@@ -0,0 +1,211 @@
+package lyrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyncedLine is one lyrics line. Time is its timestamp as written in the
+// file, before the offset is applied; untimed lines have Timed false.
+type SyncedLine struct {
+	Time  time.Duration
+	Timed bool
+	Text  string
+}
+
+// LRC is a parsed .lrc file. Offset follows the [offset:] tag semantics: a
+// positive offset makes lyrics appear sooner.
+type LRC struct {
+	Artist string
+	Title  string
+	Album  string
+	Offset time.Duration
+	Lines  []SyncedLine
+}
+
+var (
+	timeTagRe = regexp.MustCompile(`^\[(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	idTagRe   = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]\s*$`)
+)
+
+// ParseLRC parses line-synced LRC; word-level timestamps are not supported.
+// A line with several timestamps, as used for repeated choruses, yields one
+// line per timestamp. Lines are returned in playback order. Blank lines
+// between lyrics lines, one per run, are kept as untimed empty lines, so
+// Text keeps the stanza breaks.
+func ParseLRC(data string) (LRC, error) {
+	var (
+		lrc      LRC
+		lastTime time.Duration
+		blank    bool
+	)
+	data = strings.TrimPrefix(data, "\uFEFF")
+	for i, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			blank = len(lrc.Lines) > 0
+			continue
+		}
+		if blank && !idTagRe.MatchString(line) {
+			lrc.Lines = append(lrc.Lines, SyncedLine{Time: lastTime})
+			blank = false
+		}
+
+		times := make([]time.Duration, 0, 1)
+		for {
+			match := timeTagRe.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			t, err := parseTimeTag(match[1], match[2], match[3])
+			if err != nil {
+				return LRC{}, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			times = append(times, t)
+			line = line[len(match[0]):]
+		}
+
+		if len(times) == 0 {
+			if match := idTagRe.FindStringSubmatch(line); match != nil {
+				if err := lrc.setTag(strings.ToLower(match[1]), strings.TrimSpace(match[2])); err != nil {
+					return LRC{}, fmt.Errorf("line %d: %w", i+1, err)
+				}
+				continue
+			}
+			// untimed lines keep their place after the previous timed one
+			lrc.Lines = append(lrc.Lines, SyncedLine{Time: lastTime, Text: line})
+			continue
+		}
+
+		text := strings.TrimSpace(line)
+		for _, t := range times {
+			lrc.Lines = append(lrc.Lines, SyncedLine{Time: t, Timed: true, Text: text})
+		}
+		lastTime = times[0]
+	}
+	sort.SliceStable(lrc.Lines, func(i, j int) bool {
+		return lrc.Lines[i].Time < lrc.Lines[j].Time
+	})
+	return lrc, nil
+}
+
+func (l *LRC) setTag(tag, value string) error {
+	switch tag {
+	case "ar":
+		l.Artist = value
+	case "ti":
+		l.Title = value
+	case "al":
+		l.Album = value
+	case "offset":
+		ms, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+		if err != nil {
+			return fmt.Errorf("incorrect offset %q", value)
+		}
+		l.Offset = time.Duration(ms) * time.Millisecond
+	}
+	// other tags ([by:], [length:], [re:], ...) are not stored
+	return nil
+}
+
+func parseTimeTag(minutes, seconds, fraction string) (time.Duration, error) {
+	m, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0, err
+	}
+	s, err := strconv.Atoi(seconds)
+	if err != nil || s >= 60 {
+		return 0, fmt.Errorf("incorrect timestamp %s:%s", minutes, seconds)
+	}
+	t := time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	if fraction != "" {
+		f, err := strconv.Atoi(fraction)
+		if err != nil {
+			return 0, err
+		}
+		// .x is tenths, .xx hundredths, .xxx milliseconds
+		for i := len(fraction); i < 3; i++ {
+			f *= 10
+		}
+		t += time.Duration(f) * time.Millisecond
+	}
+	return t, nil
+}
+
+// Text returns the plain lyrics, one line per synced line.
+func (l LRC) Text() string {
+	lines := make([]string, 0, len(l.Lines))
+	for _, line := range l.Lines {
+		lines = append(lines, line.Text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String formats l as LRC with [mm:ss.xx] timestamps.
+func (l LRC) String() string {
+	var b strings.Builder
+	if l.Artist != "" {
+		fmt.Fprintf(&b, "[ar:%s]\n", l.Artist)
+	}
+	if l.Title != "" {
+		fmt.Fprintf(&b, "[ti:%s]\n", l.Title)
+	}
+	if l.Album != "" {
+		fmt.Fprintf(&b, "[al:%s]\n", l.Album)
+	}
+	if l.Offset != 0 {
+		fmt.Fprintf(&b, "[offset:%+d]\n", l.Offset.Milliseconds())
+	}
+	for _, line := range l.Lines {
+		if line.Timed {
+			b.WriteString(FormatTimestamp(line.Time))
+		}
+		b.WriteString(line.Text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// FormatTimestamp formats t as an LRC time tag, e.g. [01:02.50].
+func FormatTimestamp(t time.Duration) string {
+	if t < 0 {
+		t = 0
+	}
+	centis := t.Milliseconds() / 10
+	return fmt.Sprintf("[%02d:%02d.%02d]", centis/6000, centis/100%60, centis%100)
+}
+
+// ActiveLine returns the index of the timed line being sung at position,
+// i.e. the last one that started at or before it, taking the offset into
+// account. ok is false before the first timed line.
+func (l LRC) ActiveLine(position time.Duration) (int, bool) {
+	active, ok := -1, false
+	for i, line := range l.Lines {
+		if !line.Timed {
+			continue
+		}
+		if line.Time-l.Offset > position {
+			break
+		}
+		active, ok = i, true
+	}
+	return active, ok
+}
+
+// ParsePosition accepts a playback position either in seconds ("83.5") or
+// as mm:ss[.xx] ("01:23.50").
+func ParsePosition(value string) (time.Duration, error) {
+	if minutes, rest, ok := strings.Cut(value, ":"); ok {
+		seconds, fraction, _ := strings.Cut(rest, ".")
+		return parseTimeTag(minutes, seconds, fraction)
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("incorrect position %q", value)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,251 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ms(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  LRC
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  LRC{},
+		},
+		{
+			name:  "tags and lines",
+			input: "[ar:Muse]\n[ti:Hysteria]\n[al:Absolution]\n[by:someone]\n[00:01.00]It's bugging me\n[00:03.50]Grating me\n",
+			want: LRC{Artist: "Muse", Title: "Hysteria", Album: "Absolution", Lines: []SyncedLine{
+				{Time: ms(1000), Timed: true, Text: "It's bugging me"},
+				{Time: ms(3500), Timed: true, Text: "Grating me"},
+			}},
+		},
+		{
+			name:  "fractions are tenths, hundredths or milliseconds",
+			input: "[00:01.5]a\n[00:02.05]b\n[00:03.005]c\n[00:04:25]d\n[00:05]e",
+			want: LRC{Lines: []SyncedLine{
+				{Time: ms(1500), Timed: true, Text: "a"},
+				{Time: ms(2050), Timed: true, Text: "b"},
+				{Time: ms(3005), Timed: true, Text: "c"},
+				{Time: ms(4250), Timed: true, Text: "d"},
+				{Time: ms(5000), Timed: true, Text: "e"},
+			}},
+		},
+		{
+			name:  "repeated line with several timestamps",
+			input: "[00:10.00][00:30.00]chorus\n[00:20.00]verse",
+			want: LRC{Lines: []SyncedLine{
+				{Time: ms(10000), Timed: true, Text: "chorus"},
+				{Time: ms(20000), Timed: true, Text: "verse"},
+				{Time: ms(30000), Timed: true, Text: "chorus"},
+			}},
+		},
+		{
+			name:  "untimed lines follow the previous timed one",
+			input: "intro\n[00:02.00]a\nb\n[00:01.00]c",
+			want: LRC{Lines: []SyncedLine{
+				{Time: 0, Text: "intro"},
+				{Time: ms(1000), Timed: true, Text: "c"},
+				{Time: ms(2000), Timed: true, Text: "a"},
+				{Time: ms(2000), Text: "b"},
+			}},
+		},
+		{
+			name:  "positive offset",
+			input: "[offset:+500]\n[00:01.00]a",
+			want:  LRC{Offset: ms(500), Lines: []SyncedLine{{Time: ms(1000), Timed: true, Text: "a"}}},
+		},
+		{
+			name:  "negative offset",
+			input: "[offset:-250]\n[00:01.00]a",
+			want:  LRC{Offset: ms(-250), Lines: []SyncedLine{{Time: ms(1000), Timed: true, Text: "a"}}},
+		},
+		{
+			name:  "offset without sign, upper case tag",
+			input: "[OFFSET: 100 ]\n[00:01.00]a",
+			want:  LRC{Offset: ms(100), Lines: []SyncedLine{{Time: ms(1000), Timed: true, Text: "a"}}},
+		},
+		{
+			name:  "bom, crlf and blank lines",
+			input: "\uFEFF[ti:Song]\r\n\r\n[00:01.00] a \r\n",
+			want:  LRC{Title: "Song", Lines: []SyncedLine{{Time: ms(1000), Timed: true, Text: "a"}}},
+		},
+		{
+			name:  "blank lines between stanzas are kept once",
+			input: "[ar:Muse]\n\n[00:01.00]a\n[00:02.00]b\n\n \n[00:05.00]c\nuntimed\n\n[00:07.00]d\n\n\n",
+			want: LRC{Artist: "Muse", Lines: []SyncedLine{
+				{Time: ms(1000), Timed: true, Text: "a"},
+				{Time: ms(2000), Timed: true, Text: "b"},
+				{Time: ms(2000), Text: ""},
+				{Time: ms(5000), Timed: true, Text: "c"},
+				{Time: ms(5000), Text: "untimed"},
+				{Time: ms(5000), Text: ""},
+				{Time: ms(7000), Timed: true, Text: "d"},
+			}},
+		},
+		{
+			name:  "blank line before a tag waits for the next lyrics line",
+			input: "[00:01.00]a\n\n[offset:100]\n[00:02.00]b",
+			want: LRC{Offset: ms(100), Lines: []SyncedLine{
+				{Time: ms(1000), Timed: true, Text: "a"},
+				{Time: ms(1000), Text: ""},
+				{Time: ms(2000), Timed: true, Text: "b"},
+			}},
+		},
+		{
+			name:  "timestamp without text",
+			input: "[00:01.00]a\n[00:02.00]\n[00:03.00]b",
+			want: LRC{Lines: []SyncedLine{
+				{Time: ms(1000), Timed: true, Text: "a"},
+				{Time: ms(2000), Timed: true, Text: ""},
+				{Time: ms(3000), Timed: true, Text: "b"},
+			}},
+		},
+	}
+	for _, test := range tests {
+		got, err := ParseLRC(test.input)
+		if err != nil {
+			t.Errorf("%s: ParseLRC error = %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ParseLRC = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseLRCErrors(t *testing.T) {
+	tests := []string{
+		"[00:60.00]a",
+		"[00:01.00]a\n[offset:soon]",
+		"[offset:1.5]",
+	}
+	for _, input := range tests {
+		if lrc, err := ParseLRC(input); err == nil {
+			t.Errorf("ParseLRC(%q) = %+v, want an error", input, lrc)
+		}
+	}
+}
+
+func TestLRCRoundTrip(t *testing.T) {
+	tests := []LRC{
+		{},
+		{Artist: "Кино", Title: "Группа крови", Album: "Группа крови", Offset: ms(-1500), Lines: []SyncedLine{
+			{Time: 0, Text: "untimed first"},
+			{Time: ms(12340), Timed: true, Text: "Тёплое место"},
+			{Time: ms(12340), Text: "untimed after"},
+			{Time: ms(65 * 1000), Timed: true, Text: ""},
+			{Time: ms(99*60*1000 + 59990), Timed: true, Text: "late [bracketed] line"},
+		}},
+		{Offset: ms(250), Lines: []SyncedLine{{Time: ms(10), Timed: true, Text: "a"}}},
+		{Lines: []SyncedLine{
+			{Time: ms(1000), Timed: true, Text: "a"},
+			{Time: ms(1000), Text: ""},
+			{Time: ms(2000), Timed: true, Text: "b"},
+		}},
+	}
+	for _, lrc := range tests {
+		got, err := ParseLRC(lrc.String())
+		if err != nil {
+			t.Errorf("ParseLRC(%q) error = %v", lrc.String(), err)
+			continue
+		}
+		if !reflect.DeepEqual(got, lrc) {
+			t.Errorf("ParseLRC(%q) = %+v, want %+v", lrc.String(), got, lrc)
+		}
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	tests := []struct {
+		input time.Duration
+		want  string
+	}{
+		{0, "[00:00.00]"},
+		{-time.Second, "[00:00.00]"},
+		{ms(1005), "[00:01.00]"},
+		{ms(62500), "[01:02.50]"},
+		{ms(100*60*1000 + 1230), "[100:01.23]"},
+	}
+	for _, test := range tests {
+		if got := FormatTimestamp(test.input); got != test.want {
+			t.Errorf("FormatTimestamp(%v) = %s, want %s", test.input, got, test.want)
+		}
+	}
+}
+
+func TestActiveLine(t *testing.T) {
+	lrc := LRC{Lines: []SyncedLine{
+		{Time: 0, Text: "untimed"},
+		{Time: ms(1000), Timed: true, Text: "a"},
+		{Time: ms(2000), Timed: true, Text: "b"},
+		{Time: ms(2000), Text: "untimed b"},
+		{Time: ms(3000), Timed: true, Text: "c"},
+	}}
+	tests := []struct {
+		offset   time.Duration
+		position time.Duration
+		want     int
+		ok       bool
+	}{
+		{0, ms(999), -1, false},
+		{0, ms(1000), 1, true},
+		{0, ms(2500), 2, true},
+		{0, time.Hour, 4, true},
+		// a positive offset shows lines sooner, a negative one later
+		{ms(500), ms(500), 1, true},
+		{ms(500), ms(2499), 2, true},
+		{ms(-500), ms(1499), -1, false},
+		{ms(-500), ms(1500), 1, true},
+	}
+	for _, test := range tests {
+		lrc.Offset = test.offset
+		got, ok := lrc.ActiveLine(test.position)
+		if got != test.want || ok != test.ok {
+			t.Errorf("offset %v: ActiveLine(%v) = %d, %v, want %d, %v", test.offset, test.position, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+		err   bool
+	}{
+		{"0", 0, false},
+		{"83.5", ms(83500), false},
+		{"01:23.50", ms(83500), false},
+		{"1:23", ms(83000), false},
+		{"-1", 0, true},
+		{"01:60", 0, true},
+		{"soon", 0, true},
+		{"", 0, true},
+	}
+	for _, test := range tests {
+		got, err := ParsePosition(test.input)
+		if (err != nil) != test.err || got != test.want {
+			t.Errorf("ParsePosition(%q) = %v, %v, want %v, error %v", test.input, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestLRCTextKeepsStanzas(t *testing.T) {
+	lrc, err := ParseLRC("[00:01.00]a\n[00:02.00]b\n\n[00:03.00]c\n[00:04.00]d\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := lrc.Text(), "a\nb\n\nc\nd"; got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+	if stanzas := SplitStanzas(lrc.Text()); len(stanzas) != 2 {
+		t.Errorf("Text has %d stanzas, want 2", len(stanzas))
+	}
+}
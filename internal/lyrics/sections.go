@@ -0,0 +1,133 @@
+package lyrics
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	KindVerse  = "verse"
+	KindChorus = "chorus"
+	KindBridge = "bridge"
+	KindIntro  = "intro"
+	KindOutro  = "outro"
+)
+
+var Kinds = []string{KindVerse, KindChorus, KindBridge, KindIntro, KindOutro}
+
+// Section is a typed part of the lyrics. Number counts sections of the same
+// kind from 1; Position is the 1-based order in the song.
+type Section struct {
+	Position int
+	Kind     string
+	Number   int
+	Text     string
+}
+
+// markerRe matches marker lines like "[Chorus]", "[Verse 2]", "[Куплет 1:]".
+var markerRe = regexp.MustCompile(`^\s*\[\s*([^\]\d:]+?)\s*(\d+)?\s*:?\s*\]\s*$`)
+
+var markerKinds = map[string]string{
+	"verse":      KindVerse,
+	"куплет":     KindVerse,
+	"chorus":     KindChorus,
+	"refrain":    KindChorus,
+	"hook":       KindChorus,
+	"припев":     KindChorus,
+	"bridge":     KindBridge,
+	"бридж":      KindBridge,
+	"intro":      KindIntro,
+	"вступление": KindIntro,
+	"outro":      KindOutro,
+	"концовка":   KindOutro,
+}
+
+// ParseSections splits lyrics into sections. Marker lines such as "[Chorus]"
+// start a section of that kind. Stanzas without a marker are choruses if the
+// same stanza occurs more than once in the song and verses otherwise.
+func ParseSections(text string) []Section {
+	type block struct {
+		kind   string
+		number int
+		lines  []string
+	}
+	blocks := make([]block, 0)
+	var current *block
+	for _, stanza := range SplitStanzas(text) {
+		// a marker on its own, "[Chorus]" and a blank line, heads the next stanza
+		if current == nil || len(current.lines) > 0 {
+			current = nil
+		}
+		for _, line := range stanza.Lines {
+			if kind, number, ok := parseMarker(line); ok {
+				blocks = append(blocks, block{kind: kind, number: number})
+				current = &blocks[len(blocks)-1]
+				continue
+			}
+			if current == nil {
+				blocks = append(blocks, block{})
+				current = &blocks[len(blocks)-1]
+			}
+			current.lines = append(current.lines, line)
+		}
+	}
+
+	occurrences := make(map[string]int)
+	for _, b := range blocks {
+		occurrences[normalizeStanza(b.lines)]++
+	}
+
+	sections := make([]Section, 0, len(blocks))
+	counters := make(map[string]int)
+	for _, b := range blocks {
+		if len(b.lines) == 0 {
+			continue
+		}
+		kind := b.kind
+		if kind == "" {
+			kind = KindVerse
+			if occurrences[normalizeStanza(b.lines)] > 1 {
+				kind = KindChorus
+			}
+		}
+		counters[kind]++
+		number := b.number
+		if number == 0 {
+			number = counters[kind]
+		} else {
+			counters[kind] = number
+		}
+		sections = append(sections, Section{
+			Position: len(sections) + 1,
+			Kind:     kind,
+			Number:   number,
+			Text:     strings.Join(b.lines, "\n"),
+		})
+	}
+	return sections
+}
+
+func parseMarker(line string) (string, int, bool) {
+	match := markerRe.FindStringSubmatch(line)
+	if match == nil {
+		return "", 0, false
+	}
+	kind, ok := markerKinds[strings.ToLower(strings.TrimSpace(match[1]))]
+	if !ok {
+		return "", 0, false
+	}
+	number := 0
+	if match[2] != "" {
+		number, _ = strconv.Atoi(match[2])
+	}
+	return kind, number, true
+}
+
+func normalizeStanza(lines []string) string {
+	normalized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		normalized = append(normalized, strings.ToLower(strings.Join(strings.Fields(line), " ")))
+	}
+	return strings.Join(normalized, "\n")
+}
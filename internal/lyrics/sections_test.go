@@ -0,0 +1,86 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSections(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Section
+	}{
+		{"empty", "", []Section{}},
+		{
+			name:  "repeated stanzas without markers are choruses",
+			input: "verse one\n\nLa la\nla  la\n\nverse two\n\nla la\nLa La",
+			want: []Section{
+				{Position: 1, Kind: KindVerse, Number: 1, Text: "verse one"},
+				{Position: 2, Kind: KindChorus, Number: 1, Text: "La la\nla  la"},
+				{Position: 3, Kind: KindVerse, Number: 2, Text: "verse two"},
+				{Position: 4, Kind: KindChorus, Number: 2, Text: "la la\nLa La"},
+			},
+		},
+		{
+			name:  "markers set kind and number",
+			input: "[Intro]\nooh\n\n[Verse 2]\nb\n\n[Chorus]\nc\n\n[Verse]\nd\n\n[Outro:]\nbye",
+			want: []Section{
+				{Position: 1, Kind: KindIntro, Number: 1, Text: "ooh"},
+				{Position: 2, Kind: KindVerse, Number: 2, Text: "b"},
+				{Position: 3, Kind: KindChorus, Number: 1, Text: "c"},
+				{Position: 4, Kind: KindVerse, Number: 3, Text: "d"},
+				{Position: 5, Kind: KindOutro, Number: 1, Text: "bye"},
+			},
+		},
+		{
+			name:  "russian markers",
+			input: "[Куплет 1:]\nа\n\n[Припев]\nб\n\n[Бридж]\nв",
+			want: []Section{
+				{Position: 1, Kind: KindVerse, Number: 1, Text: "а"},
+				{Position: 2, Kind: KindChorus, Number: 1, Text: "б"},
+				{Position: 3, Kind: KindBridge, Number: 1, Text: "в"},
+			},
+		},
+		{
+			name:  "marker in the middle of a stanza starts a section",
+			input: "a\n[Chorus]\nb\nc",
+			want: []Section{
+				{Position: 1, Kind: KindVerse, Number: 1, Text: "a"},
+				{Position: 2, Kind: KindChorus, Number: 1, Text: "b\nc"},
+			},
+		},
+		{
+			name:  "marker followed by a blank line heads the next stanza",
+			input: "[Verse]\na\n\n[Chorus]\n\nb\nb\n\n[Verse 2]\n\n\nc",
+			want: []Section{
+				{Position: 1, Kind: KindVerse, Number: 1, Text: "a"},
+				{Position: 2, Kind: KindChorus, Number: 1, Text: "b\nb"},
+				{Position: 3, Kind: KindVerse, Number: 2, Text: "c"},
+			},
+		},
+		{
+			name:  "marker heads one stanza only",
+			input: "[Chorus]\n\na\n\nb",
+			want: []Section{
+				{Position: 1, Kind: KindChorus, Number: 1, Text: "a"},
+				{Position: 2, Kind: KindVerse, Number: 1, Text: "b"},
+			},
+		},
+		{
+			name:  "markers without lines are dropped",
+			input: "[Intro]\n[Verse]\na\n\n[Outro]",
+			want:  []Section{{Position: 1, Kind: KindVerse, Number: 1, Text: "a"}},
+		},
+		{
+			name:  "unknown bracketed lines are lyrics",
+			input: "[Solo]\n[Verse 1a]\na",
+			want:  []Section{{Position: 1, Kind: KindVerse, Number: 1, Text: "[Solo]\n[Verse 1a]\na"}},
+		},
+	}
+	for _, test := range tests {
+		if got := ParseSections(test.input); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ParseSections(%q) = %+v, want %+v", test.name, test.input, got, test.want)
+		}
+	}
+}
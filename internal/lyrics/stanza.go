@@ -0,0 +1,31 @@
+package lyrics
+
+import "strings"
+
+// Stanza is a block of consecutive non-blank lines. Line numbers are 1-based
+// positions in the original text, blank lines included.
+type Stanza struct {
+	StartLine int
+	EndLine   int
+	Lines     []string
+}
+
+// SplitStanzas splits text into stanzas separated by one or more blank lines.
+func SplitStanzas(text string) []Stanza {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	stanzas := make([]Stanza, 0)
+	var current *Stanza
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			stanzas = append(stanzas, Stanza{StartLine: i + 1})
+			current = &stanzas[len(stanzas)-1]
+		}
+		current.EndLine = i + 1
+		current.Lines = append(current.Lines, line)
+	}
+	return stanzas
+}
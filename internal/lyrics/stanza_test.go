@@ -0,0 +1,37 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStanzas(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Stanza
+	}{
+		{"empty", "", []Stanza{}},
+		{"blank lines only", "\n  \n\t\n", []Stanza{}},
+		{"one line", "a", []Stanza{{StartLine: 1, EndLine: 1, Lines: []string{"a"}}}},
+		{
+			name:  "blank lines separate stanzas",
+			input: "a\nb\n\n\nc\n \nd\n",
+			want: []Stanza{
+				{StartLine: 1, EndLine: 2, Lines: []string{"a", "b"}},
+				{StartLine: 5, EndLine: 5, Lines: []string{"c"}},
+				{StartLine: 7, EndLine: 7, Lines: []string{"d"}},
+			},
+		},
+		{
+			name:  "crlf and leading blank lines",
+			input: "\r\n\r\n  a\r\nb  \r\n",
+			want:  []Stanza{{StartLine: 3, EndLine: 4, Lines: []string{"  a", "b  "}}},
+		},
+	}
+	for _, test := range tests {
+		if got := SplitStanzas(test.input); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: SplitStanzas(%q) = %+v, want %+v", test.name, test.input, got, test.want)
+		}
+	}
+}
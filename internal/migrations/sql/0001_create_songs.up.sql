@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS songs (
+    id           bigserial PRIMARY KEY,
+    group_name   text,
+    song         text,
+    release_date text,
+    text         text,
+    link         text
+);
@@ -0,0 +1,17 @@
+ALTER TABLE songs ADD COLUMN status text NOT NULL DEFAULT 'enriched';
+
+CREATE TABLE enrichment_jobs (
+    id           bigserial PRIMARY KEY,
+    song_id      bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    status       text NOT NULL DEFAULT 'queued',
+    attempts     integer NOT NULL DEFAULT 0,
+    max_attempts integer NOT NULL,
+    last_error   text NOT NULL DEFAULT '',
+    run_at       timestamptz NOT NULL DEFAULT now(),
+    locked_until timestamptz,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX enrichment_jobs_ready_idx ON enrichment_jobs (run_at) WHERE status IN ('queued', 'running');
+CREATE INDEX enrichment_jobs_song_id_idx ON enrichment_jobs (song_id);
@@ -0,0 +1,6 @@
+ALTER TABLE songs ADD COLUMN field_sources jsonb NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE songs ADD COLUMN enriched_at timestamptz;
+
+UPDATE songs SET enriched_at = now() WHERE status = 'enriched';
+
+CREATE INDEX songs_enriched_at_idx ON songs (enriched_at) WHERE status = 'enriched';
@@ -0,0 +1,11 @@
+CREATE TABLE lyrics_sections (
+    id       bigserial PRIMARY KEY,
+    song_id  bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    kind     text NOT NULL,
+    number   integer NOT NULL,
+    text     text NOT NULL,
+    UNIQUE (song_id, position)
+);
+
+CREATE INDEX lyrics_sections_song_kind_idx ON lyrics_sections (song_id, kind, number);
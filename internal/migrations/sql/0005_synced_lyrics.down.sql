@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS lyrics_timings;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS lyrics_offset_ms;
@@ -0,0 +1,8 @@
+ALTER TABLE songs ADD COLUMN lyrics_offset_ms integer NOT NULL DEFAULT 0;
+
+CREATE TABLE lyrics_timings (
+    song_id bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    line    integer NOT NULL,
+    time_ms integer NOT NULL,
+    PRIMARY KEY (song_id, line)
+);
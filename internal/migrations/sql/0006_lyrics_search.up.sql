@@ -0,0 +1,8 @@
+ALTER TABLE songs ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(song, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(song, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(text, '')), 'B') ||
+    setweight(to_tsvector('russian', coalesce(text, '')), 'B')
+) STORED;
+
+CREATE INDEX songs_search_vector_idx ON songs USING gin (search_vector);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS songs_song_trgm_idx;
+DROP INDEX IF EXISTS songs_group_name_trgm_idx;
+
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE, which index expressions do not allow; pinning
+-- the dictionary makes the wrapper safe to declare IMMUTABLE.
+CREATE OR REPLACE FUNCTION immutable_unaccent(value text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent'::regdictionary, value)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE INDEX songs_group_name_trgm_idx ON songs USING gin (immutable_unaccent(lower(group_name)) gin_trgm_ops);
+CREATE INDEX songs_song_trgm_idx ON songs USING gin (immutable_unaccent(lower(song)) gin_trgm_ops);
@@ -0,0 +1,8 @@
+CREATE INDEX songs_group_name_trgm_idx ON songs USING gin (immutable_unaccent(lower(group_name)) gin_trgm_ops);
+CREATE INDEX songs_song_trgm_idx ON songs USING gin (immutable_unaccent(lower(song)) gin_trgm_ops);
+
+DROP INDEX IF EXISTS songs_song_key_trgm_idx;
+DROP INDEX IF EXISTS songs_group_key_trgm_idx;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS song_key;
+ALTER TABLE songs DROP COLUMN IF EXISTS group_key;
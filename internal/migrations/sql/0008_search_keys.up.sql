@@ -0,0 +1,11 @@
+-- Script-independent search keys, computed by the application with
+-- translit.Key; rows written before this migration are backfilled on start.
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS group_key text;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS song_key text;
+
+CREATE INDEX songs_group_key_trgm_idx ON songs USING gin (group_key gin_trgm_ops);
+CREATE INDEX songs_song_key_trgm_idx ON songs USING gin (song_key gin_trgm_ops);
+
+-- fuzzy search compares the keys now
+DROP INDEX IF EXISTS songs_group_name_trgm_idx;
+DROP INDEX IF EXISTS songs_song_trgm_idx;
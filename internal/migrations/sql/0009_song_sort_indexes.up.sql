@@ -0,0 +1,6 @@
+-- Back the common song listing sorts; the expressions must match
+-- sortColumns in the store. id is the tie-breaker of every sort.
+CREATE INDEX songs_group_sort_idx ON songs ((coalesce(group_name, '')), id);
+CREATE INDEX songs_song_sort_idx ON songs ((coalesce(song, '')), id);
+CREATE INDEX songs_release_date_sort_idx ON songs
+    ((coalesce(right(release_date, 4) || substr(release_date, 4, 2) || left(release_date, 2), '')), id);
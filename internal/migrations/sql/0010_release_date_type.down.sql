@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS songs_release_date_sort_idx;
+
+ALTER TABLE songs ALTER COLUMN release_date TYPE text USING to_char(release_date, 'DD.MM.YYYY');
+
+CREATE INDEX songs_release_date_sort_idx ON songs
+    ((coalesce(right(release_date, 4) || substr(release_date, 4, 2) || left(release_date, 2), '')), id);
@@ -0,0 +1,26 @@
+-- release_date was free text validated as DD.MM.YYYY by the service. Values
+-- that are not a real date in any accepted layout become NULL.
+CREATE FUNCTION pg_temp.parse_release_date(value text) RETURNS date AS $$
+BEGIN
+    value := btrim(value);
+    IF value ~ '^\d{1,2}\.\d{1,2}\.\d{4}$' THEN
+        RETURN make_date(split_part(value, '.', 3)::int, split_part(value, '.', 2)::int, split_part(value, '.', 1)::int);
+    ELSIF value ~ '^\d{4}-\d{2}-\d{2}' THEN
+        RETURN make_date(substr(value, 1, 4)::int, substr(value, 6, 2)::int, substr(value, 9, 2)::int);
+    ELSIF value ~ '^\d{4}$' THEN
+        RETURN make_date(value::int, 1, 1);
+    END IF;
+    RETURN NULL;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+-- the sort index expression only works on text
+DROP INDEX IF EXISTS songs_release_date_sort_idx;
+
+ALTER TABLE songs ALTER COLUMN release_date TYPE date USING pg_temp.parse_release_date(release_date);
+
+DROP FUNCTION pg_temp.parse_release_date(text);
+
+CREATE INDEX songs_release_date_sort_idx ON songs ((coalesce(release_date, date '0001-01-01')), id);
@@ -0,0 +1,35 @@
+-- Artists get their own table; songs.group_name stays as a cached copy of
+-- the artist name. name_key ignores case and extra whitespace, so "Muse"
+-- and "muse " are one artist.
+CREATE TABLE artists (
+    id         bigserial PRIMARY KEY,
+    name       text NOT NULL,
+    name_key   text GENERATED ALWAYS AS (lower(regexp_replace(btrim(name), '\s+', ' ', 'g'))) STORED,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX artists_name_key_idx ON artists (name_key);
+
+-- one artist per distinct key, named after its most common spelling
+INSERT INTO artists (name)
+SELECT DISTINCT ON (name_key) name
+FROM (
+    SELECT regexp_replace(btrim(group_name), '\s+', ' ', 'g') AS name,
+           lower(regexp_replace(btrim(group_name), '\s+', ' ', 'g')) AS name_key,
+           count(*) AS songs
+    FROM songs
+    WHERE btrim(coalesce(group_name, '')) <> ''
+    GROUP BY 1, 2
+) AS spellings
+ORDER BY name_key, songs DESC, name;
+
+ALTER TABLE songs ADD COLUMN artist_id bigint REFERENCES artists (id) ON DELETE RESTRICT;
+
+UPDATE songs
+SET artist_id = artists.id,
+    group_name = artists.name
+FROM artists
+WHERE artists.name_key = lower(regexp_replace(btrim(songs.group_name), '\s+', ' ', 'g'));
+
+CREATE INDEX songs_artist_id_idx ON songs (artist_id);
@@ -0,0 +1,26 @@
+-- An album belongs to at most one artist; compilations have none. Titles
+-- are unique per artist ignoring case, so enrichment can find an album
+-- again by its title.
+CREATE TABLE albums (
+    id           bigserial PRIMARY KEY,
+    title        text NOT NULL,
+    artist_id    bigint REFERENCES artists (id) ON DELETE RESTRICT,
+    release_date date,
+    cover_link   text NOT NULL DEFAULT '',
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX albums_artist_title_idx ON albums ((coalesce(artist_id, 0)), (lower(btrim(title))));
+
+-- A song may appear on several albums, once per album.
+CREATE TABLE album_tracks (
+    album_id     bigint NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    song_id      bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    disc_number  integer NOT NULL DEFAULT 1 CHECK (disc_number > 0),
+    track_number integer NOT NULL CHECK (track_number > 0),
+    PRIMARY KEY (album_id, song_id),
+    UNIQUE (album_id, disc_number, track_number)
+);
+
+CREATE INDEX album_tracks_song_id_idx ON album_tracks (song_id);
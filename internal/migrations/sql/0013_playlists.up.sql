@@ -0,0 +1,24 @@
+-- version grows with every change of a playlist or its entries; clients
+-- send the version they saw to detect concurrent edits.
+CREATE TABLE playlists (
+    id          bigserial PRIMARY KEY,
+    name        text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    version     integer NOT NULL DEFAULT 1,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now()
+);
+
+-- A song may be in a playlist several times, so entries have their own id.
+-- Positions run from 1 without gaps; the unique check is deferred to commit
+-- so that shifting positions does not trip over itself.
+CREATE TABLE playlist_entries (
+    id          bigserial PRIMARY KEY,
+    playlist_id bigint NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    song_id     bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position    integer NOT NULL CHECK (position > 0),
+    added_at    timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT playlist_entries_position_key UNIQUE (playlist_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX playlist_entries_song_id_idx ON playlist_entries (song_id);
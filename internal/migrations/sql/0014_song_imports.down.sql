@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS song_import_rows;
+
+DROP TABLE IF EXISTS song_imports;
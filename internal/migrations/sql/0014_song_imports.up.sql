@@ -0,0 +1,31 @@
+-- A bulk song import and its report: the counters sum up song_import_rows,
+-- which keeps the outcome of every row of the file.
+CREATE TABLE song_imports (
+    id                bigserial PRIMARY KEY,
+    status            text NOT NULL DEFAULT 'running',
+    format            text NOT NULL,
+    total             integer NOT NULL DEFAULT 0,
+    inserted          integer NOT NULL DEFAULT 0,
+    duplicates        integer NOT NULL DEFAULT 0,
+    enrichment_failed integer NOT NULL DEFAULT 0,
+    invalid           integer NOT NULL DEFAULT 0,
+    error             text NOT NULL DEFAULT '',
+    created_at        timestamptz NOT NULL DEFAULT now(),
+    updated_at        timestamptz NOT NULL DEFAULT now(),
+    finished_at       timestamptz
+);
+
+-- line is where the row starts in the file. The song is kept for inserted
+-- and duplicate rows; deleting it later only clears the reference.
+CREATE TABLE song_import_rows (
+    import_id  bigint NOT NULL REFERENCES song_imports (id) ON DELETE CASCADE,
+    line       integer NOT NULL,
+    group_name text NOT NULL DEFAULT '',
+    song       text NOT NULL DEFAULT '',
+    song_id    bigint REFERENCES songs (id) ON DELETE SET NULL,
+    status     text NOT NULL,
+    error      text NOT NULL DEFAULT '',
+    PRIMARY KEY (import_id, line)
+);
+
+CREATE INDEX song_import_rows_song_id_idx ON song_import_rows (song_id);
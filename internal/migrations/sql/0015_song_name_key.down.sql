@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS songs_name_key_unique_idx;
+
+DROP INDEX IF EXISTS songs_name_key_idx;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS name_key;
@@ -0,0 +1,14 @@
+-- name_key is the group and song name as store.SongNameKey builds it: the
+-- translit keys, or the lower-cased name for a name without letters and
+-- digits, whose key is empty. It stays NULL until the keys are backfilled on
+-- start.
+ALTER TABLE songs ADD COLUMN name_key text GENERATED ALWAYS AS (
+    CASE WHEN group_key = '' THEN '=' || lower(btrim(group_name)) ELSE group_key END
+    || '/' ||
+    CASE WHEN song_key = '' THEN '=' || lower(btrim(song)) ELSE song_key END
+) STORED;
+
+-- Not unique: songs that share a name already are left for someone to merge
+-- through the api. The service creates the unique index on name_key once
+-- none are left, see store.ensureSongNameIndex.
+CREATE INDEX songs_name_key_idx ON songs (name_key);
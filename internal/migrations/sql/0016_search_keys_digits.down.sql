@@ -0,0 +1,6 @@
+-- the old rules collapse repeated digits again, so the keys are backfilled
+-- with them on start
+UPDATE songs
+SET group_key = NULL,
+    song_key = NULL
+WHERE group_name ~ '(\d)[^[:alnum:]]*\1' OR song ~ '(\d)[^[:alnum:]]*\1';
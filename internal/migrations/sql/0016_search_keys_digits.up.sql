@@ -0,0 +1,7 @@
+-- translit.Key collapsed repeated digits too, so "Би-22" had the key of
+-- "Би-2". Keys that may have lost a digit are cleared and backfilled on
+-- start with the fixed rules.
+UPDATE songs
+SET group_key = NULL,
+    song_key = NULL
+WHERE group_name ~ '(\d)[^[:alnum:]]*\1' OR song ~ '(\d)[^[:alnum:]]*\1';
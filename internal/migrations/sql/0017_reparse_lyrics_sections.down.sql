@@ -0,0 +1 @@
+-- nothing to undo: the sections parsed on start stay
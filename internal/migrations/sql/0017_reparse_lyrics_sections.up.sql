@@ -0,0 +1,4 @@
+-- A marker on its own line before a blank line, "[Chorus]" then an empty
+-- line, was dropped. Sections are derived from songs.text, so they are
+-- cleared and parsed again on start.
+DELETE FROM lyrics_sections;
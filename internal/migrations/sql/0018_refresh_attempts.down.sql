@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS songs_refresh_attempted_at_idx;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS refresh_attempted_at;
@@ -0,0 +1,9 @@
+-- When the refresh scheduler last took the song, whether enriching it
+-- worked or not. It picks songs by this instead of enriched_at, so songs
+-- that keep failing no longer come first on every tick. New songs count as
+-- taken when they are inserted.
+ALTER TABLE songs ADD COLUMN refresh_attempted_at timestamptz NOT NULL DEFAULT now();
+
+UPDATE songs SET refresh_attempted_at = coalesce(enriched_at, 'epoch');
+
+CREATE INDEX songs_refresh_attempted_at_idx ON songs (refresh_attempted_at) WHERE status = 'enriched';
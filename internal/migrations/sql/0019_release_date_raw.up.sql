@@ -0,0 +1,5 @@
+-- release_date_raw holds the text of release dates that 0010 could not
+-- parse, so that they can be fixed by hand. 0010 itself drops that text:
+-- the column has values only on databases that kept it while converting,
+-- and is empty elsewhere. Setting a release date clears it.
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS release_date_raw text;
@@ -0,0 +1,136 @@
+package playlistfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// m3uLine writes a line of extended M3U. Plain M3U is Latin-1, as players
+// expect; characters it lacks become "?", so M3U8 is the one to use for
+// anything but Latin script.
+func m3uLine(buf *bufio.Writer, latin1 bool, format string, args ...interface{}) error {
+	text := fmt.Sprintf(format, args...)
+	if latin1 {
+		text = toLatin1(text)
+	}
+	_, err := buf.WriteString(text)
+	return err
+}
+
+// m3uTrack writes the #EXTINF and location lines of a track; M3U entries
+// need a location, so a track without one is left out.
+func m3uTrack(buf *bufio.Writer, latin1 bool, track Track) error {
+	if track.Location == "" {
+		return nil
+	}
+	seconds := -1
+	if track.Duration > 0 {
+		seconds = int(track.Duration.Round(time.Second) / time.Second)
+	}
+	return m3uLine(buf, latin1, "#EXTINF:%d,%s\n%s\n", seconds, oneLine(joinTitle(track.Group, track.Song)), oneLine(track.Location))
+}
+
+func oneLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// toLatin1 encodes text in Latin-1, replacing what it lacks with "?".
+func toLatin1(text string) string {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r > 0xFF {
+			r = '?'
+		}
+		encoded = append(encoded, byte(r))
+	}
+	return string(encoded)
+}
+
+// parseM3U reads plain and extended M3U. Text that is not UTF-8 is taken
+// for Latin-1. An #EXTINF line gives the title of the location that
+// follows it; a location without one is titled after its file name.
+func parseM3U(data []byte) (string, []Track, error) {
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+	text := string(data)
+	if !utf8.Valid(data) {
+		decoded, err := charmap.ISO8859_1.NewDecoder().Bytes(data)
+		if err != nil {
+			return "", nil, err
+		}
+		text = string(decoded)
+	}
+
+	var (
+		title   string
+		tracks  = make([]Track, 0)
+		pending *Track
+	)
+	for i, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			track, err := parseExtInf(strings.TrimPrefix(line, "#EXTINF:"))
+			if err != nil {
+				return "", nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			pending = &track
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			var track Track
+			if pending != nil {
+				track = *pending
+				pending = nil
+			} else {
+				track.Group, track.Song = splitTitle(titleFromLocation(line))
+			}
+			track.Location = line
+			tracks = append(tracks, track)
+		}
+	}
+	if pending != nil {
+		tracks = append(tracks, *pending)
+	}
+	return title, tracks, nil
+}
+
+// parseExtInf parses "duration [attributes],title". Attribute values are
+// quoted and may hold commas.
+func parseExtInf(info string) (Track, error) {
+	quoted := false
+	comma := -1
+	for i, r := range info {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ',' && !quoted {
+			comma = i
+			break
+		}
+	}
+	if comma < 0 {
+		return Track{}, fmt.Errorf("#EXTINF has no title")
+	}
+	var track Track
+	if fields := strings.Fields(info[:comma]); len(fields) > 0 {
+		seconds, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return Track{}, fmt.Errorf("incorrect #EXTINF duration %q", fields[0])
+		}
+		if seconds > 0 {
+			track.Duration = time.Duration(seconds * float64(time.Second))
+		}
+	}
+	track.Group, track.Song = splitTitle(info[comma+1:])
+	return track, nil
+}
@@ -0,0 +1,97 @@
+package playlistfile
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseM3U(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		title  string
+		tracks []Track
+	}{
+		{"empty", "", "", []Track{}},
+		{
+			name:  "plain locations are titled after their files",
+			input: "Muse - Uprising.mp3\r\n\r\n# a comment\r\nmusic/Hysteria.mp3\r\n",
+			tracks: []Track{
+				{Group: "Muse", Song: "Uprising", Location: "Muse - Uprising.mp3"},
+				{Song: "Hysteria", Location: "music/Hysteria.mp3"},
+			},
+		},
+		{
+			name:  "extended",
+			input: "\uFEFF#EXTM3U\n#PLAYLIST: Mix \n#EXTINF:305,Muse - Uprising\nuprising.mp3\n#EXTINF:-1,Hysteria\n#EXTVLCOPT:start-time=10\nhysteria.mp3\n",
+			title: "Mix",
+			tracks: []Track{
+				{Group: "Muse", Song: "Uprising", Location: "uprising.mp3", Duration: 305 * time.Second},
+				{Song: "Hysteria", Location: "hysteria.mp3"},
+			},
+		},
+		{
+			name:  "fractional duration",
+			input: "#EXTINF:12.5,A - B\nb.mp3",
+			tracks: []Track{
+				{Group: "A", Song: "B", Location: "b.mp3", Duration: 12500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "quoted commas in attributes",
+			input: `#EXTINF:200 tvg-name="Earth, Wind & Fire" group-title="70s, disco",Earth, Wind & Fire - September` + "\nseptember.mp3",
+			tracks: []Track{
+				{Group: "Earth, Wind & Fire", Song: "September", Location: "september.mp3", Duration: 200 * time.Second},
+			},
+		},
+		{
+			name:  "title with a dash only splits on the first",
+			input: "#EXTINF:1,A - B - C\nc.mp3",
+			tracks: []Track{
+				{Group: "A", Song: "B - C", Location: "c.mp3", Duration: time.Second},
+			},
+		},
+		{
+			name:  "latin-1",
+			input: "#EXTM3U\n#PLAYLIST:Caf\xe9\n#EXTINF:60,Bj\xf6rk - J\xf3ga\nBj\xf6rk/J\xf3ga.mp3\n",
+			title: "Café",
+			tracks: []Track{
+				{Group: "Björk", Song: "Jóga", Location: "Björk/Jóga.mp3", Duration: time.Minute},
+			},
+		},
+		{
+			name:  "#EXTINF without a location ends the file",
+			input: "a.mp3\n#EXTINF:5,A - B",
+			tracks: []Track{
+				{Song: "a", Location: "a.mp3"},
+				{Group: "A", Song: "B", Duration: 5 * time.Second},
+			},
+		},
+	}
+	for _, test := range tests {
+		title, tracks, err := parseM3U([]byte(test.input))
+		if err != nil {
+			t.Errorf("%s: parseM3U(%q) error = %v", test.name, test.input, err)
+			continue
+		}
+		if title != test.title || !reflect.DeepEqual(tracks, test.tracks) {
+			t.Errorf("%s: parseM3U(%q) = %q, %+v, want %q, %+v", test.name, test.input, title, tracks, test.title, test.tracks)
+		}
+	}
+}
+
+func TestParseM3UErrors(t *testing.T) {
+	tests := []string{
+		"#EXTINF:300\na.mp3",
+		`#EXTINF:300 tvg-name="a,b"` + "\na.mp3",
+		"#EXTINF:long,A - B\na.mp3",
+		// the duration comes first and is required when there are attributes
+		`#EXTINF: tvg-id="1",A - B` + "\na.mp3",
+	}
+	for _, input := range tests {
+		if _, tracks, err := parseM3U([]byte(input)); err == nil {
+			t.Errorf("parseM3U(%q) = %+v, want an error", input, tracks)
+		}
+	}
+}
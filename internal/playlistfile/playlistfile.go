@@ -0,0 +1,183 @@
+// Package playlistfile reads and writes the playlist files media players
+// exchange: extended M3U, its UTF-8 flavour M3U8, and XSPF.
+package playlistfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Formats, named after their file extensions.
+const (
+	FormatM3U  = "m3u"
+	FormatM3U8 = "m3u8"
+	FormatXSPF = "xspf"
+)
+
+// Track is a playlist entry as media players see it.
+type Track struct {
+	Group    string
+	Song     string
+	Location string
+	// Duration is zero when unknown.
+	Duration time.Duration
+}
+
+// IsFormat tells whether format is one of the supported formats.
+func IsFormat(format string) bool {
+	switch format {
+	case FormatM3U, FormatM3U8, FormatXSPF:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the media type of a format.
+func ContentType(format string) string {
+	switch format {
+	case FormatM3U:
+		return "audio/x-mpegurl"
+	case FormatM3U8:
+		return "application/vnd.apple.mpegurl"
+	default:
+		return "application/xspf+xml"
+	}
+}
+
+// Write writes tracks as a playlist titled title. M3U entries need a
+// location, so tracks without one are left out of M3U and M3U8.
+func Write(w io.Writer, format, title string, tracks []Track) error {
+	writer, err := NewWriter(w, format, title)
+	if err != nil {
+		return err
+	}
+	for _, track := range tracks {
+		if err := writer.Write(track); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// Writer writes a playlist track by track, so that a long one need not be
+// held in memory. Close finishes the file, it does not close the underlying
+// writer.
+type Writer struct {
+	format string
+	buf    *bufio.Writer
+	// xml writes XSPF to buf
+	xml *xml.Encoder
+}
+
+// NewWriter starts a playlist titled title, like Write does.
+func NewWriter(w io.Writer, format, title string) (*Writer, error) {
+	writer := &Writer{
+		format: format,
+		buf:    bufio.NewWriter(w),
+	}
+	var err error
+	switch format {
+	case FormatM3U, FormatM3U8:
+		err = m3uLine(writer.buf, format == FormatM3U, "#EXTM3U\n")
+		if err == nil && title != "" {
+			err = m3uLine(writer.buf, format == FormatM3U, "#PLAYLIST:%s\n", oneLine(title))
+		}
+	case FormatXSPF:
+		writer.xml = xml.NewEncoder(writer.buf)
+		writer.xml.Indent("", "  ")
+		err = xspfStart(writer.buf, writer.xml, title)
+	default:
+		return nil, fmt.Errorf("unsupported playlist format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *Writer) Write(track Track) error {
+	if w.xml != nil {
+		return w.xml.EncodeElement(xspfEntry(track), xml.StartElement{Name: xml.Name{Local: "track"}})
+	}
+	return m3uTrack(w.buf, w.format == FormatM3U, track)
+}
+
+// Close ends the playlist and flushes.
+func (w *Writer) Close() error {
+	if w.xml != nil {
+		if err := xspfEnd(w.xml); err != nil {
+			return err
+		}
+		if err := w.buf.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return w.buf.Flush()
+}
+
+// Parse reads a playlist; an empty format is guessed with Detect. The title
+// is empty if the file has none.
+func Parse(data []byte, format string) (string, []Track, error) {
+	if format == "" {
+		format = Detect(data)
+	}
+	switch format {
+	case FormatM3U, FormatM3U8:
+		return parseM3U(data)
+	case FormatXSPF:
+		return parseXSPF(data)
+	default:
+		return "", nil, fmt.Errorf("unsupported playlist format %q", format)
+	}
+}
+
+// Detect guesses the format of a playlist: XML is XSPF, valid UTF-8 is
+// M3U8, anything else M3U.
+func Detect(data []byte) string {
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return FormatXSPF
+	}
+	if utf8.Valid(data) {
+		return FormatM3U8
+	}
+	return FormatM3U
+}
+
+// joinTitle and splitTitle convert between group and song and the
+// "Group - Song" titles players show.
+func joinTitle(group, song string) string {
+	if group == "" {
+		return song
+	}
+	return group + " - " + song
+}
+
+func splitTitle(title string) (string, string) {
+	title = strings.TrimSpace(title)
+	if group, song, ok := strings.Cut(title, " - "); ok {
+		return strings.TrimSpace(group), strings.TrimSpace(song)
+	}
+	return "", title
+}
+
+// titleFromLocation makes a title of a file name such as
+// "Muse - Uprising.mp3" for entries that have nothing better.
+func titleFromLocation(location string) string {
+	if parsed, err := url.Parse(location); err == nil && parsed.Scheme != "" && parsed.Path != "" {
+		location = parsed.Path
+	}
+	if unescaped, err := url.PathUnescape(location); err == nil {
+		location = unescaped
+	}
+	name := path.Base(strings.ReplaceAll(location, `\`, "/"))
+	return strings.TrimSuffix(name, path.Ext(name))
+}
@@ -0,0 +1,110 @@
+package playlistfile
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tracks := []Track{
+		{Group: "Muse", Song: "Uprising", Location: "music/Muse - Uprising.mp3", Duration: 305 * time.Second},
+		{Group: "Björk", Song: "Jóga", Location: "http://example.com/jóga.mp3"},
+		{Group: "AC/DC", Song: "T.N.T., live", Location: `C:\music\tnt.flac`, Duration: 214 * time.Second},
+		{Song: "Untitled", Location: "untitled.ogg"},
+	}
+	for _, format := range []string{FormatM3U, FormatM3U8, FormatXSPF} {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, "Mix <1> & more", tracks); err != nil {
+			t.Fatalf("%s: Write error = %v", format, err)
+		}
+		if detected := Detect(buf.Bytes()); detected != format {
+			t.Errorf("%s: Detect = %s", format, detected)
+		}
+		title, got, err := Parse(buf.Bytes(), "")
+		if err != nil {
+			t.Errorf("%s: Parse(%q) error = %v", format, buf.String(), err)
+			continue
+		}
+		if title != "Mix <1> & more" {
+			t.Errorf("%s: title = %q", format, title)
+		}
+		if !reflect.DeepEqual(got, tracks) {
+			t.Errorf("%s: Parse(%q) = %+v, want %+v", format, buf.String(), got, tracks)
+		}
+	}
+}
+
+func TestWriteM3UWithoutLocations(t *testing.T) {
+	tracks := []Track{
+		{Group: "Кино", Song: "Кукушка", Location: "kino.mp3", Duration: 1500 * time.Millisecond},
+		{Group: "Muse", Song: "Uprising"},
+		{Group: "Muse", Song: "Hysteria\nlive", Location: "hysteria.mp3"},
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatM3U, "#EXTM3U\n#PLAYLIST:Плейлист ?\n#EXTINF:2,???? - ???????\nkino.mp3\n#EXTINF:-1,Muse - Hysteria live\nhysteria.mp3\n"},
+		{FormatM3U8, "#EXTM3U\n#PLAYLIST:Плейлист ?\n#EXTINF:2,Кино - Кукушка\nkino.mp3\n#EXTINF:-1,Muse - Hysteria live\nhysteria.mp3\n"},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := Write(&buf, test.format, "Плейлист ?", tracks); err != nil {
+			t.Fatal(err)
+		}
+		want := test.want
+		if test.format == FormatM3U {
+			want = toLatin1(want)
+		}
+		if buf.String() != want {
+			t.Errorf("%s: Write = %q, want %q", test.format, buf.String(), want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", FormatM3U8},
+		{"#EXTM3U\nsong.mp3\n", FormatM3U8},
+		{"\uFEFF  <?xml version=\"1.0\"?><playlist/>", FormatXSPF},
+		{"#EXTM3U\nBj\xf6rk.mp3\n", FormatM3U},
+	}
+	for _, test := range tests {
+		if got := Detect([]byte(test.input)); got != test.want {
+			t.Errorf("Detect(%q) = %s, want %s", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseUnsupportedFormat(t *testing.T) {
+	if _, _, err := Parse([]byte("#EXTM3U"), "pls"); err == nil {
+		t.Errorf("Parse with format pls succeeded")
+	}
+	if err := Write(&bytes.Buffer{}, "pls", "", nil); err == nil {
+		t.Errorf("Write with format pls succeeded")
+	}
+}
+
+func TestTitleFromLocation(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Muse - Uprising.mp3", "Muse - Uprising"},
+		{"/music/Muse/Muse - Uprising.flac", "Muse - Uprising"},
+		{`C:\Music\Muse - Uprising.mp3`, "Muse - Uprising"},
+		{"http://example.com/music/Muse%20-%20Uprising.mp3?token=1", "Muse - Uprising"},
+		{"file:///home/me/%D0%9A%D0%B8%D0%BD%D0%BE.ogg", "Кино"},
+		{"no extension", "no extension"},
+	}
+	for _, test := range tests {
+		if got := titleFromLocation(test.input); got != test.want {
+			t.Errorf("titleFromLocation(%q) = %q, want %q", test.input, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package playlistfile
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const xspfNamespace = "http://xspf.org/ns/0/"
+
+// xspfPlaylist is the part of XSPF version 1 the service reads. The element
+// names carry no namespace, so files that omit it are read as well.
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Locations []string `xml:"location"`
+	Title     string   `xml:"title,omitempty"`
+	Creator   string   `xml:"creator,omitempty"`
+	// Duration is in milliseconds.
+	Duration int64 `xml:"duration,omitempty"`
+}
+
+func xspfEntry(track Track) xspfTrack {
+	entry := xspfTrack{
+		Title:    track.Song,
+		Creator:  track.Group,
+		Duration: track.Duration.Milliseconds(),
+	}
+	if track.Location != "" {
+		entry.Locations = []string{track.Location}
+	}
+	return entry
+}
+
+// xspfStart writes the XML header and opens the playlist and its track list.
+func xspfStart(buf io.Writer, encoder *xml.Encoder, title string) error {
+	if _, err := io.WriteString(buf, xml.Header); err != nil {
+		return err
+	}
+	err := encoder.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "playlist"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns"}, Value: xspfNamespace},
+			{Name: xml.Name{Local: "version"}, Value: "1"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if title != "" {
+		if err := encoder.EncodeElement(title, xml.StartElement{Name: xml.Name{Local: "title"}}); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(xml.StartElement{Name: xml.Name{Local: "trackList"}})
+}
+
+// xspfEnd closes what xspfStart opened.
+func xspfEnd(encoder *xml.Encoder) error {
+	for _, name := range []string{"trackList", "playlist"} {
+		if err := encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return encoder.Flush()
+}
+
+// parseXSPF reads an XSPF playlist. A track without a creator has its
+// group and song split from the title, or from the location if it has no
+// title either.
+func parseXSPF(data []byte) (string, []Track, error) {
+	var playlist xspfPlaylist
+	if err := xml.Unmarshal(data, &playlist); err != nil {
+		return "", nil, fmt.Errorf("error with decoding xspf: %w", err)
+	}
+	tracks := make([]Track, 0, len(playlist.Tracks))
+	for _, entry := range playlist.Tracks {
+		track := Track{
+			Group: strings.TrimSpace(entry.Creator),
+			Song:  strings.TrimSpace(entry.Title),
+		}
+		if len(entry.Locations) > 0 {
+			track.Location = strings.TrimSpace(entry.Locations[0])
+		}
+		if entry.Duration > 0 {
+			track.Duration = time.Duration(entry.Duration) * time.Millisecond
+		}
+		if track.Group == "" {
+			title := track.Song
+			if title == "" {
+				title = titleFromLocation(track.Location)
+			}
+			track.Group, track.Song = splitTitle(title)
+		}
+		tracks = append(tracks, track)
+	}
+	return strings.TrimSpace(playlist.Title), tracks, nil
+}
@@ -0,0 +1,85 @@
+package playlistfile
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseXSPF(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		title  string
+		tracks []Track
+	}{
+		{
+			name:   "empty playlist",
+			input:  `<playlist version="1" xmlns="http://xspf.org/ns/0/"/>`,
+			tracks: []Track{},
+		},
+		{
+			name: "creator and title",
+			input: `<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <title> Mix </title>
+  <trackList>
+    <track>
+      <location>http://example.com/a.mp3</location>
+      <location>http://mirror.example.com/a.mp3</location>
+      <creator>Muse</creator>
+      <title>Uprising</title>
+      <duration>305000</duration>
+    </track>
+  </trackList>
+</playlist>`,
+			title: "Mix",
+			tracks: []Track{
+				{Group: "Muse", Song: "Uprising", Location: "http://example.com/a.mp3", Duration: 305 * time.Second},
+			},
+		},
+		{
+			name: "without namespace, creator or title",
+			input: `<playlist version="1"><trackList>
+<track><title>Muse - Hysteria</title></track>
+<track><location>file:///music/Muse%20-%20Starlight.mp3</location></track>
+<track><title>Intro</title><duration>-5</duration></track>
+</trackList></playlist>`,
+			tracks: []Track{
+				{Group: "Muse", Song: "Hysteria"},
+				{Group: "Muse", Song: "Starlight", Location: "file:///music/Muse%20-%20Starlight.mp3"},
+				{Song: "Intro"},
+			},
+		},
+		{
+			name:   "escaped text",
+			input:  `<playlist version="1"><title>R&amp;B &lt;3</title><trackList><track><creator>Simon &amp; Garfunkel</creator><title>Mrs. Robinson</title></track></trackList></playlist>`,
+			title:  "R&B <3",
+			tracks: []Track{{Group: "Simon & Garfunkel", Song: "Mrs. Robinson"}},
+		},
+	}
+	for _, test := range tests {
+		title, tracks, err := parseXSPF([]byte(test.input))
+		if err != nil {
+			t.Errorf("%s: parseXSPF error = %v", test.name, err)
+			continue
+		}
+		if title != test.title || !reflect.DeepEqual(tracks, test.tracks) {
+			t.Errorf("%s: parseXSPF = %q, %+v, want %q, %+v", test.name, title, tracks, test.title, test.tracks)
+		}
+	}
+}
+
+func TestParseXSPFErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"<playlist><trackList>",
+		"<rss version=\"2.0\"></rss>",
+		"<playlist><trackList><track><duration>long</duration></track></trackList></playlist>",
+	}
+	for _, input := range tests {
+		if _, tracks, err := parseXSPF([]byte(input)); err == nil {
+			t.Errorf("parseXSPF(%q) = %+v, want an error", input, tracks)
+		}
+	}
+}
@@ -1,16 +1,101 @@
 package server
 
 import (
+	"context"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-func NewServer(handler http.Handler, port string) error {
-	server := http.Server{
+type Server struct {
+	httpServer   *http.Server
+	drainTimeout time.Duration
+	inFlight     sync.WaitGroup
+	// inFlightCount mirrors inFlight, whose count cannot be read.
+	inFlightCount  atomic.Int64
+	onDrainTimeout []func()
+}
+
+func NewServer(handler http.Handler, port string, drainTimeout time.Duration) *Server {
+	s := &Server{
+		drainTimeout: drainTimeout,
+	}
+	s.httpServer = &http.Server{
 		Addr:         ":" + port,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
-		Handler:      handler,
+		Handler:      s.track(handler),
 	}
-	return server.ListenAndServe()
+	return s
+}
+
+// Run serves until Shutdown is called. It returns nil after a clean shutdown.
+func (s *Server) Run() error {
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// RegisterOnDrainTimeout registers f to be called when handlers are still
+// running after the drain timeout, e.g. to cancel their outbound calls.
+func (s *Server) RegisterOnDrainTimeout(f func()) {
+	s.onDrainTimeout = append(s.onDrainTimeout, f)
+}
+
+// Shutdown stops accepting connections and waits for in-flight handlers.
+// Handlers that outlive the drain timeout have their outbound calls
+// cancelled via the RegisterOnDrainTimeout hooks and are then waited for
+// once more as long, or until ctx is done, so the caller can release
+// resources they use afterwards. Handlers still running then are abandoned
+// and Shutdown returns ErrHandlersAbandoned; the resources they use must
+// not be released.
+func (s *Server) Shutdown(ctx context.Context) error {
+	log := logger.LoggerFromContext(ctx)
+	drainCtx, cancel := context.WithTimeout(ctx, s.drainTimeout)
+	defer cancel()
+
+	err := s.httpServer.Shutdown(drainCtx)
+	if err != nil {
+		for _, f := range s.onDrainTimeout {
+			f()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(s.drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		log.Warnw("handlers are still running after cancelling them, abandoning", "count", s.inFlightCount.Load())
+		return projectError.ErrHandlersAbandoned
+	case <-ctx.Done():
+		log.Warnw("shutdown is cancelled, abandoning running handlers", "count", s.inFlightCount.Load())
+		return projectError.ErrHandlersAbandoned
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) track(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.inFlight.Add(1)
+		s.inFlightCount.Add(1)
+		defer func() {
+			s.inFlightCount.Add(-1)
+			s.inFlight.Done()
+		}()
+		handler.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"effectiveMobile/internal/entities"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// encodeCursor makes an opaque cursor token clients pass back verbatim.
+func encodeCursor(cursor entities.SongCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (entities.SongCursor, error) {
+	var cursor entities.SongCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/filter"
+	"effectiveMobile/internal/store"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseFilters turns the raw GetSongs filter params into store filters.
+// releaseDate, year and decade each become a range of release dates; all
+// the ranges and releasedFrom/releasedTo are intersected. A year alone as
+// releaseDate or releasedTo means the whole year. A bad filter expression is
+// ErrIncorrectFilter wrapping the *filter.SyntaxError.
+func parseFilters(params entities.SongFilterParams) (entities.SongFilters, error) {
+	filters := entities.SongFilters{
+		GroupName: params.GroupName,
+		Song:      params.Song,
+		Text:      params.Text,
+		Link:      params.Link,
+	}
+	expr, err := filter.Parse(params.Filter, store.SongFilterFields)
+	if err != nil {
+		return filters, fmt.Errorf("%w: %w", errors.ErrIncorrectFilter, err)
+	}
+	filters.Expr = expr
+	if params.ArtistID != "" {
+		artistId, err := strconv.Atoi(params.ArtistID)
+		if err != nil || artistId < 1 {
+			return filters, errors.ErrIncorrectRequest
+		}
+		filters.ArtistID = uint(artistId)
+	}
+	narrow := func(from, to entities.Date) {
+		if !from.IsZero() && (filters.ReleasedFrom.IsZero() || from.After(filters.ReleasedFrom.Time)) {
+			filters.ReleasedFrom = from
+		}
+		if !to.IsZero() && (filters.ReleasedTo.IsZero() || to.Before(filters.ReleasedTo.Time)) {
+			filters.ReleasedTo = to
+		}
+	}
+
+	if params.ReleaseDate != "" {
+		if year, ok := parseYear(params.ReleaseDate); ok {
+			narrow(yearRange(year, 1))
+		} else {
+			date, err := entities.ParseDate(params.ReleaseDate)
+			if err != nil {
+				return filters, err
+			}
+			narrow(date, date)
+		}
+	}
+	if params.ReleasedFrom != "" {
+		date, err := entities.ParseDate(params.ReleasedFrom)
+		if err != nil {
+			return filters, err
+		}
+		narrow(date, entities.Date{})
+	}
+	if params.ReleasedTo != "" {
+		if year, ok := parseYear(params.ReleasedTo); ok {
+			_, to := yearRange(year, 1)
+			narrow(entities.Date{}, to)
+		} else {
+			date, err := entities.ParseDate(params.ReleasedTo)
+			if err != nil {
+				return filters, err
+			}
+			narrow(entities.Date{}, date)
+		}
+	}
+	if params.Year != "" {
+		year, ok := parseYear(params.Year)
+		if !ok {
+			return filters, fmt.Errorf("incorrect year %q", params.Year)
+		}
+		narrow(yearRange(year, 1))
+	}
+	if params.Decade != "" {
+		// 1990 and 1990s both mean 1990-1999
+		decade, ok := parseYear(strings.TrimSuffix(params.Decade, "s"))
+		if !ok || decade%10 != 0 {
+			return filters, fmt.Errorf("incorrect decade %q", params.Decade)
+		}
+		narrow(yearRange(decade, 10))
+	}
+	return filters, nil
+}
+
+// parseYear accepts a four-digit year.
+func parseYear(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) != 4 {
+		return 0, false
+	}
+	year, err := strconv.Atoi(value)
+	if err != nil || year < 1 {
+		return 0, false
+	}
+	return year, true
+}
+
+// yearRange returns the first and the last day of years starting with year.
+func yearRange(year, years int) (entities.Date, entities.Date) {
+	return entities.NewDate(year, time.January, 1), entities.NewDate(year+years-1, time.December, 31)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/enrichment"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/store"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RefreshScheduler periodically re-enriches songs whose metadata is older
+// than maxAge, a batch per tick.
+type RefreshScheduler struct {
+	store    store.Songs
+	enricher *enrichment.Chain
+	interval time.Duration
+	maxAge   time.Duration
+	batch    int
+}
+
+func NewRefreshScheduler(store store.Songs, enricher *enrichment.Chain, cfg config.Config) *RefreshScheduler {
+	return &RefreshScheduler{
+		store:    store,
+		enricher: enricher,
+		interval: time.Duration(cfg.RefreshInterval) * time.Minute,
+		maxAge:   time.Duration(cfg.RefreshMaxAge) * time.Hour,
+		batch:    cfg.RefreshBatch,
+	}
+}
+
+// Run blocks until ctx is done.
+func (r *RefreshScheduler) Run(ctx context.Context) {
+	log := logger.LoggerFromContext(ctx)
+	log.Infow("refresh scheduler is started", "interval", r.interval, "maxAge", r.maxAge)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Infow("refresh scheduler is stopped")
+			return
+		case <-ticker.C:
+			r.refreshStale(ctx)
+		}
+	}
+}
+
+func (r *RefreshScheduler) refreshStale(ctx context.Context) {
+	log := logger.LoggerFromContext(ctx)
+	songs, err := r.store.GetStaleSongs(ctx, time.Now().Add(-r.maxAge), r.batch)
+	if err != nil {
+		return
+	}
+	refreshed := 0
+	for _, song := range songs {
+		if ctx.Err() != nil {
+			return
+		}
+		songCtx := logger.ContextWithLogger(ctx, log.With("songId", song.ID))
+		if _, err := refreshSong(songCtx, r.store, r.enricher, song); err != nil {
+			log.Warnw("error with refreshing song", "songId", song.ID, zap.Error(err))
+			continue
+		}
+		refreshed++
+	}
+	if len(songs) > 0 {
+		log.Infow("stale songs are refreshed", "refreshed", refreshed, "stale", len(songs))
+	}
+}
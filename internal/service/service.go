@@ -2,24 +2,98 @@ package service
 
 import (
 	"context"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/enrichment"
 	"effectiveMobile/internal/entities"
 	"effectiveMobile/internal/store"
 )
 
 type Service struct {
 	Songs
+	Jobs
+	Artists
+	Albums
+	Playlists
+	Imports
+	imports *ImportService
 }
 
-func NewService(store *store.Store, songApiUrl string, apiTimeout int) *Service {
+// NewService creates the service level. Outbound calls to the song api are
+// cancelled once ctx is done.
+func NewService(ctx context.Context, store *store.Store, enricher *enrichment.Chain, cfg config.Config) *Service {
+	songs := NewSongService(ctx, store.Songs, store.Jobs, enricher, cfg)
+	imports := NewImportService(ctx, store.Imports, store.Songs, enricher, cfg)
 	return &Service{
-		Songs: NewSongService(store.Songs, songApiUrl, apiTimeout),
+		Songs:     songs,
+		Jobs:      NewJobService(store.Jobs),
+		Artists:   NewArtistService(store.Artists, cfg.PageMaxLimit),
+		Albums:    NewAlbumService(store.Albums, cfg.PageMaxLimit),
+		Playlists: NewPlaylistService(store.Playlists, store.Songs, songs, cfg),
+		Imports:   imports,
+		imports:   imports,
 	}
 }
 
+// Wait blocks until the song imports that requests left running in the
+// background have stopped; they stop once ctx of NewService is done.
+func (s *Service) Wait() {
+	s.imports.Wait()
+}
+
 type Songs interface {
-	InsertSong(ctx context.Context, req entities.SongRequest) (int, error)
-	GetSongs(ctx context.Context, filters entities.Song, limit, offset string) ([]entities.Song, error)
+	InsertSong(ctx context.Context, req entities.SongRequest) (entities.InsertResponse, error)
+	GetSongs(ctx context.Context, params entities.SongFilterParams, sort, limit, offset, count string) (entities.SongsPage, error)
+	GetSongsPage(ctx context.Context, params entities.SongFilterParams, sort, limit, cursor, count string) (entities.SongsPage, error)
 	DeleteSong(ctx context.Context, songId string) error
 	GetTextSong(ctx context.Context, lineInVerse, page, limit, songId string) (string, error)
+	GetVerses(ctx context.Context, page, limit, songId string) (entities.VersesResponse, error)
+	GetSections(ctx context.Context, songId, kind, number string) ([]entities.LyricsSection, error)
+	ImportLRC(ctx context.Context, songId, data string) error
+	ExportLRC(ctx context.Context, songId string) (string, error)
+	GetActiveLine(ctx context.Context, songId, position string) (entities.ActiveLineResponse, error)
 	UpdateSong(ctx context.Context, songId string, song entities.SongUpdate) error
+	RefreshSong(ctx context.Context, songId string) (entities.Song, error)
+	SearchLyrics(ctx context.Context, query, lang, limit, page string) (entities.SearchPage, error)
+	FuzzySearch(ctx context.Context, query, field, threshold, limit, page string) (entities.FuzzyPage, error)
+	ExportSongs(ctx context.Context, params entities.SongFilterParams, sort, format string) (entities.SongExport, error)
+	FindDuplicates(ctx context.Context, threshold, limit, page string) (entities.DuplicatesPage, error)
+	MergeSongs(ctx context.Context, songId string, req entities.MergeRequest) (entities.Song, error)
+}
+
+type Jobs interface {
+	GetJob(ctx context.Context, jobId string) (entities.EnrichmentJob, error)
+	RetryJob(ctx context.Context, jobId string) error
+}
+
+type Artists interface {
+	InsertArtist(ctx context.Context, req entities.ArtistRequest) (entities.Artist, error)
+	GetArtists(ctx context.Context, name, limit, page string) (entities.ArtistsPage, error)
+	GetArtist(ctx context.Context, artistId string) (entities.Artist, error)
+	RenameArtist(ctx context.Context, artistId string, req entities.ArtistRequest) (entities.Artist, error)
+	DeleteArtist(ctx context.Context, artistId string) error
+}
+
+type Albums interface {
+	InsertAlbum(ctx context.Context, req entities.AlbumRequest) (entities.Album, error)
+	GetAlbums(ctx context.Context, artistId, title, limit, page string) (entities.AlbumsPage, error)
+	GetAlbum(ctx context.Context, albumId string) (entities.Album, error)
+}
+
+type Playlists interface {
+	InsertPlaylist(ctx context.Context, req entities.PlaylistRequest) (entities.Playlist, error)
+	GetPlaylists(ctx context.Context, limit, page string) (entities.PlaylistsPage, error)
+	GetPlaylist(ctx context.Context, playlistId string) (entities.Playlist, error)
+	UpdatePlaylist(ctx context.Context, playlistId string, update entities.PlaylistUpdate) (entities.Playlist, error)
+	DeletePlaylist(ctx context.Context, playlistId, version string) error
+	AddEntry(ctx context.Context, playlistId string, req entities.PlaylistEntryRequest) (entities.Playlist, error)
+	MoveEntry(ctx context.Context, playlistId, entryId string, req entities.PlaylistMoveRequest) (entities.Playlist, error)
+	RemoveEntry(ctx context.Context, playlistId, entryId, version string) (entities.Playlist, error)
+	ReorderPlaylist(ctx context.Context, playlistId string, req entities.PlaylistOrderRequest) (entities.Playlist, error)
+	ExportPlaylist(ctx context.Context, playlistId, format string) (entities.PlaylistFile, error)
+	ImportPlaylist(ctx context.Context, name, format string, data []byte) (entities.PlaylistImportResponse, error)
+}
+
+type Imports interface {
+	StartImport(ctx context.Context, format string, data []byte) (entities.SongImport, error)
+	GetImport(ctx context.Context, importId, status, limit, page string) (entities.SongImport, error)
 }
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/store"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type AlbumService struct {
+	store        store.Albums
+	pageMaxLimit int
+}
+
+func NewAlbumService(store store.Albums, pageMaxLimit int) *AlbumService {
+	return &AlbumService{
+		store:        store,
+		pageMaxLimit: pageMaxLimit,
+	}
+}
+
+func (s *AlbumService) InsertAlbum(ctx context.Context, req entities.AlbumRequest) (entities.Album, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("title", req.Title, "artist", req.Artist)
+	if strings.TrimSpace(req.Title) == "" {
+		log.Errorw("album title is empty")
+		return entities.Album{}, errors.ErrIncorrectRequest
+	}
+	tracks, err := numberTracks(req.Tracks)
+	if err != nil {
+		log.Errorw("error with numbering tracks", zap.Error(err))
+		return entities.Album{}, errors.ErrIncorrectRequest
+	}
+	return s.store.InsertAlbum(ctx, entities.Album{
+		Title:       req.Title,
+		Artist:      req.Artist,
+		ReleaseDate: req.ReleaseDate,
+		CoverLink:   req.CoverLink,
+		Tracks:      tracks,
+	})
+}
+
+func (s *AlbumService) GetAlbums(ctx context.Context, artistId, title, limit, page string) (entities.AlbumsPage, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("artistId", artistId, "title", title, "limit", limit, "page", page)
+	limitInt, pageInt, err := parsePage(limit, page)
+	if err != nil {
+		log.Errorw("error with parsing page", zap.Error(err))
+		return entities.AlbumsPage{}, errors.ErrIncorrectRequest
+	}
+	var artistIdInt int
+	if artistId != "" {
+		artistIdInt, err = strconv.Atoi(artistId)
+		if err != nil || artistIdInt < 1 {
+			log.Errorw("error with converting artist id to int", zap.Error(err))
+			return entities.AlbumsPage{}, errors.ErrIncorrectRequest
+		}
+	}
+	limitInt = capLimit(limitInt, s.pageMaxLimit)
+	albums, total, err := s.store.GetAlbums(ctx, uint(artistIdInt), strings.TrimSpace(title), limitInt, pageInt)
+	if err != nil {
+		return entities.AlbumsPage{}, err
+	}
+	if albums == nil {
+		albums = []entities.Album{}
+	}
+	return entities.AlbumsPage{
+		Items: albums,
+		Total: total,
+		Page:  pageInt,
+		Limit: limitInt,
+	}, nil
+}
+
+func (s *AlbumService) GetAlbum(ctx context.Context, albumId string) (entities.Album, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("albumId", albumId)
+	albumIdInt, err := strconv.Atoi(albumId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Album{}, errors.ErrIncorrectRequest
+	}
+	return s.store.GetAlbum(ctx, albumIdInt)
+}
+
+// numberTracks checks the tracklist and numbers it: a zero disc number is 1
+// and a zero track number follows the highest one on its disc so far. A
+// song or a disc and track number may appear only once.
+func numberTracks(requests []entities.AlbumTrackRequest) ([]entities.AlbumTrack, error) {
+	tracks := make([]entities.AlbumTrack, 0, len(requests))
+	last := make(map[int]int)
+	positions := make(map[[2]int]bool)
+	songs := make(map[uint]bool)
+	for i, req := range requests {
+		if req.SongID == 0 || req.DiscNumber < 0 || req.TrackNumber < 0 {
+			return nil, fmt.Errorf("track %d: incorrect song id, disc or track number", i+1)
+		}
+		if songs[req.SongID] {
+			return nil, fmt.Errorf("track %d: song %d is already on the album", i+1, req.SongID)
+		}
+		disc := max(req.DiscNumber, 1)
+		track := req.TrackNumber
+		if track == 0 {
+			track = last[disc] + 1
+		}
+		if positions[[2]int{disc, track}] {
+			return nil, fmt.Errorf("track %d: disc %d track %d is taken", i+1, disc, track)
+		}
+		songs[req.SongID] = true
+		positions[[2]int{disc, track}] = true
+		last[disc] = max(last[disc], track)
+		tracks = append(tracks, entities.AlbumTrack{
+			SongID:      req.SongID,
+			DiscNumber:  disc,
+			TrackNumber: track,
+		})
+	}
+	return tracks, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/store"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type ArtistService struct {
+	store        store.Artists
+	pageMaxLimit int
+}
+
+func NewArtistService(store store.Artists, pageMaxLimit int) *ArtistService {
+	return &ArtistService{
+		store:        store,
+		pageMaxLimit: pageMaxLimit,
+	}
+}
+
+func (s *ArtistService) InsertArtist(ctx context.Context, req entities.ArtistRequest) (entities.Artist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("name", req.Name)
+	if strings.TrimSpace(req.Name) == "" {
+		log.Errorw("artist name is empty")
+		return entities.Artist{}, errors.ErrIncorrectRequest
+	}
+	return s.store.InsertArtist(ctx, req.Name)
+}
+
+func (s *ArtistService) GetArtists(ctx context.Context, name, limit, page string) (entities.ArtistsPage, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("name", name, "limit", limit, "page", page)
+	limitInt, pageInt, err := parsePage(limit, page)
+	if err != nil {
+		log.Errorw("error with parsing page", zap.Error(err))
+		return entities.ArtistsPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt = capLimit(limitInt, s.pageMaxLimit)
+	artists, total, err := s.store.GetArtists(ctx, strings.TrimSpace(name), limitInt, pageInt)
+	if err != nil {
+		return entities.ArtistsPage{}, err
+	}
+	if artists == nil {
+		artists = []entities.Artist{}
+	}
+	return entities.ArtistsPage{
+		Items: artists,
+		Total: total,
+		Page:  pageInt,
+		Limit: limitInt,
+	}, nil
+}
+
+func (s *ArtistService) GetArtist(ctx context.Context, artistId string) (entities.Artist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("artistId", artistId)
+	artistIdInt, err := strconv.Atoi(artistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Artist{}, errors.ErrIncorrectRequest
+	}
+	return s.store.GetArtist(ctx, artistIdInt)
+}
+
+// RenameArtist renames the artist; its songs follow.
+func (s *ArtistService) RenameArtist(ctx context.Context, artistId string, req entities.ArtistRequest) (entities.Artist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("artistId", artistId, "name", req.Name)
+	artistIdInt, err := strconv.Atoi(artistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Artist{}, errors.ErrIncorrectRequest
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		log.Errorw("artist name is empty")
+		return entities.Artist{}, errors.ErrIncorrectRequest
+	}
+	return s.store.RenameArtist(ctx, artistIdInt, req.Name)
+}
+
+func (s *ArtistService) DeleteArtist(ctx context.Context, artistId string) error {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("artistId", artistId)
+	artistIdInt, err := strconv.Atoi(artistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return errors.ErrIncorrectRequest
+	}
+	return s.store.DeleteArtist(ctx, artistIdInt)
+}
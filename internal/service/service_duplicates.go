@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"slices"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// duplicatePairsMax caps the pairs FindDuplicates clusters. The best pairs
+// are taken, so with a low threshold the weakest clusters may be missing.
+const duplicatePairsMax = 1000
+
+// FindDuplicates lists clusters of songs that are likely one, best first.
+// Two songs are linked when their names are similar, and their lyrics too
+// if both have some, at threshold at least, the configured one by default;
+// songs linked through each other are one cluster.
+func (s *SongService) FindDuplicates(ctx context.Context, threshold, limit, page string) (entities.DuplicatesPage, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("threshold", threshold, "limit", limit, "page", page)
+	limitInt, pageInt, err := parsePage(limit, page)
+	if err != nil {
+		log.Errorw("error with parsing page", zap.Error(err))
+		return entities.DuplicatesPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt = s.capLimit(limitInt)
+	thresholdFloat, err := parseThreshold(threshold, s.duplicateThreshold)
+	if err != nil {
+		log.Errorw("error with parsing threshold", zap.Error(err))
+		return entities.DuplicatesPage{}, errors.ErrIncorrectRequest
+	}
+
+	pairs, err := s.store.FindDuplicates(ctx, thresholdFloat, duplicatePairsMax)
+	if err != nil {
+		return entities.DuplicatesPage{}, err
+	}
+	clusters := clusterPairs(pairs)
+	result := entities.DuplicatesPage{
+		Items: []entities.DuplicateCluster{},
+		Total: int64(len(clusters)),
+		Page:  pageInt,
+		Limit: limitInt,
+	}
+	// pages are compared rather than offsets, which overflow for huge pages
+	if pageInt > (len(clusters)+limitInt-1)/limitInt {
+		return result, nil
+	}
+	start := (pageInt - 1) * limitInt
+	clusters = clusters[start:min(start+limitInt, len(clusters))]
+
+	var ids []uint
+	for _, cluster := range clusters {
+		for _, pair := range cluster.Pairs {
+			ids = append(ids, pair.SongID, pair.OtherID)
+		}
+	}
+	slices.Sort(ids)
+	songs, err := s.store.GetSongsByIds(ctx, slices.Compact(ids))
+	if err != nil {
+		return entities.DuplicatesPage{}, err
+	}
+	byId := make(map[uint]entities.Song, len(songs))
+	for _, song := range songs {
+		byId[song.ID] = song
+	}
+	for _, cluster := range clusters {
+		ids = ids[:0]
+		for _, pair := range cluster.Pairs {
+			ids = append(ids, pair.SongID, pair.OtherID)
+		}
+		slices.Sort(ids)
+		cluster.Songs = []entities.Song{}
+		for _, id := range slices.Compact(ids) {
+			// songs deleted or merged meanwhile are left out
+			if song, ok := byId[id]; ok {
+				cluster.Songs = append(cluster.Songs, song)
+			}
+		}
+		result.Items = append(result.Items, cluster)
+	}
+	log.Infow("duplicate songs are found", "clusters", result.Total)
+	return result, nil
+}
+
+// clusterPairs groups pairs that share songs, directly or through other
+// pairs. Pairs come best first, and so do the clusters and their pairs.
+func clusterPairs(pairs []entities.DuplicatePair) []entities.DuplicateCluster {
+	parent := make(map[uint]uint)
+	find := func(id uint) uint {
+		if _, ok := parent[id]; !ok {
+			parent[id] = id
+		}
+		for parent[id] != id {
+			parent[id] = parent[parent[id]]
+			id = parent[id]
+		}
+		return id
+	}
+	for _, pair := range pairs {
+		a, b := find(pair.SongID), find(pair.OtherID)
+		if a != b {
+			parent[max(a, b)] = min(a, b)
+		}
+	}
+
+	var clusters []entities.DuplicateCluster
+	index := make(map[uint]int)
+	for _, pair := range pairs {
+		root := find(pair.SongID)
+		i, ok := index[root]
+		if !ok {
+			i = len(clusters)
+			index[root] = i
+			clusters = append(clusters, entities.DuplicateCluster{Score: pair.Score})
+		}
+		clusters[i].Pairs = append(clusters[i].Pairs, pair)
+	}
+	return clusters
+}
+
+// MergeSongs merges the songs of req into the song songId: their playlist
+// entries, album tracks and import rows go over to it and they are deleted.
+// The song itself is left as it is and returned.
+func (s *SongService) MergeSongs(ctx context.Context, songId string, req entities.MergeRequest) (entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("songId", songId, "ids", req.IDs)
+	songIdInt, err := strconv.Atoi(songId)
+	if err != nil || songIdInt <= 0 {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Song{}, errors.ErrIncorrectRequest
+	}
+	ids := slices.Clone(req.IDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) == 0 || slices.Contains(ids, uint(songIdInt)) {
+		log.Errorw("songs to merge are missing or include the song kept")
+		return entities.Song{}, errors.ErrIncorrectRequest
+	}
+	return s.store.MergeSongs(ctx, uint(songIdInt), ids)
+}
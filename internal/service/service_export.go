@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/playlistfile"
+	"effectiveMobile/internal/songfile"
+	stdErrors "errors"
+	"io"
+
+	"go.uber.org/zap"
+)
+
+// exportBatch is how many songs an export reads at a time.
+const exportBatch = 500
+
+// ExportSongs exports every song matching the GetSongs filters, in sort
+// order, streaming them as they are read. format is csv (default), ndjson
+// or json, or a playlist format, m3u, m3u8 or xspf. The export stops when
+// outboundCtx is done.
+func (s *SongService) ExportSongs(ctx context.Context, params entities.SongFilterParams, sort, format string) (entities.SongExport, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("filters", params, "sort", sort, "format", format)
+	if format == "" {
+		format = songfile.FormatCSV
+	}
+	if !songfile.IsWriteFormat(format) && !playlistfile.IsFormat(format) {
+		log.Errorw("unsupported export format")
+		return entities.SongExport{}, errors.ErrIncorrectRequest
+	}
+	filters, err := parseFilters(params)
+	if err != nil {
+		log.Errorw("error with parsing filters", zap.Error(err))
+		if stdErrors.Is(err, errors.ErrIncorrectFilter) {
+			return entities.SongExport{}, err
+		}
+		return entities.SongExport{}, errors.ErrIncorrectRequest
+	}
+	sortKeys, err := parseSort(sort)
+	if err != nil {
+		log.Errorw("error with parsing sort", zap.Error(err))
+		return entities.SongExport{}, err
+	}
+
+	export := entities.SongExport{
+		Name:        "songs." + format,
+		ContentType: songfile.ContentType(format),
+	}
+	if playlistfile.IsFormat(format) {
+		export.ContentType = playlistfile.ContentType(format)
+	}
+	export.Write = func(w io.Writer) error {
+		exportCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(s.outboundCtx, cancel)
+		defer stop()
+
+		var err error
+		if playlistfile.IsFormat(format) {
+			err = s.exportPlaylist(exportCtx, w, format, filters, sortKeys)
+		} else {
+			err = s.exportSongs(exportCtx, w, format, filters, sortKeys)
+		}
+		if err != nil {
+			log.Errorw("error with exporting songs", zap.Error(err))
+			return err
+		}
+		log.Infow("songs are exported")
+		return nil
+	}
+	return export, nil
+}
+
+func (s *SongService) exportSongs(ctx context.Context, w io.Writer, format string, filters entities.SongFilters, sort []entities.SortKey) error {
+	writer, err := songfile.NewWriter(w, format)
+	if err != nil {
+		return err
+	}
+	err = s.store.StreamSongs(ctx, filters, sort, exportBatch, func(songs []entities.Song) error {
+		for _, song := range songs {
+			if err := writer.Write(song); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// exportPlaylist writes the songs as a playlist, their links being the
+// locations.
+func (s *SongService) exportPlaylist(ctx context.Context, w io.Writer, format string, filters entities.SongFilters, sort []entities.SortKey) error {
+	writer, err := playlistfile.NewWriter(w, format, "")
+	if err != nil {
+		return err
+	}
+	err = s.store.StreamSongs(ctx, filters, sort, exportBatch, func(songs []entities.Song) error {
+		for _, song := range songs {
+			err := writer.Write(playlistfile.Track{
+				Group:    song.GroupName,
+				Song:     song.Song,
+				Location: song.Link,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/enrichment"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/songfile"
+	"effectiveMobile/internal/store"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// errImportInterrupted fails an import cut short by shutdown or by the
+// caller going away.
+var errImportInterrupted = stdErrors.New("import is interrupted, run it again to finish")
+
+// ImportService imports song files: each row is checked, looked up, enriched
+// unless the file has all its fields, and inserted. Rows go through
+// enrichment on a bounded number of workers and are saved in batches, each
+// in one transaction together with its part of the report. Songs that
+// already exist, by the same rule as FindSong, are reported as duplicates
+// and left alone, so importing a file again changes nothing.
+type ImportService struct {
+	store       store.Imports
+	songs       store.Songs
+	enricher    *enrichment.Chain
+	outboundCtx context.Context
+	workers     int
+	batch       int
+	pageMax     int
+	running     sync.WaitGroup
+}
+
+func NewImportService(outboundCtx context.Context, store store.Imports, songs store.Songs, enricher *enrichment.Chain, cfg config.Config) *ImportService {
+	return &ImportService{
+		store:       store,
+		songs:       songs,
+		enricher:    enricher,
+		outboundCtx: outboundCtx,
+		workers:     max(cfg.ImportWorkers, 1),
+		batch:       max(cfg.ImportBatch, 1),
+		pageMax:     cfg.PageMaxLimit,
+	}
+}
+
+// StartImport checks the file header, records the import and runs it in
+// the background, returning the running import. It stops when outboundCtx
+// is done; Wait waits for that.
+func (s *ImportService) StartImport(ctx context.Context, format string, data []byte) (entities.SongImport, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("format", format, "size", len(data))
+	reader, err := s.newReader(format, bytes.NewReader(data))
+	if err != nil {
+		log.Errorw("error with reading song file", zap.Error(err))
+		return entities.SongImport{}, err
+	}
+	songImport, err := s.store.InsertImport(ctx, reader.Format())
+	if err != nil {
+		return entities.SongImport{}, err
+	}
+
+	log = log.With("importId", songImport.ID)
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		_, _ = s.run(logger.ContextWithLogger(s.outboundCtx, log), songImport, reader)
+	}()
+	log.Infow("song import is started")
+	return songImport, nil
+}
+
+// RunImport imports r to the end and returns the finished import with all
+// its rows.
+func (s *ImportService) RunImport(ctx context.Context, format string, r io.Reader) (entities.SongImport, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("format", format)
+	reader, err := s.newReader(format, r)
+	if err != nil {
+		log.Errorw("error with reading song file", zap.Error(err))
+		return entities.SongImport{}, err
+	}
+	songImport, err := s.store.InsertImport(ctx, reader.Format())
+	if err != nil {
+		return entities.SongImport{}, err
+	}
+	return s.run(logger.ContextWithLogger(ctx, log.With("importId", songImport.ID)), songImport, reader)
+}
+
+// Wait blocks until the imports started by StartImport have stopped.
+func (s *ImportService) Wait() {
+	s.running.Wait()
+}
+
+func (s *ImportService) GetImport(ctx context.Context, importId, status, limit, page string) (entities.SongImport, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("importId", importId, "status", status, "limit", limit, "page", page)
+	importIdInt, err := strconv.Atoi(importId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.SongImport{}, errors.ErrIncorrectRequest
+	}
+	switch status {
+	case "", entities.RowInserted, entities.RowDuplicate, entities.RowEnrichmentFailed, entities.RowInvalid:
+	default:
+		log.Errorw("unknown row status")
+		return entities.SongImport{}, errors.ErrIncorrectRequest
+	}
+	limitInt, pageInt, err := parsePage(limit, page)
+	if err != nil {
+		log.Errorw("error with parsing page", zap.Error(err))
+		return entities.SongImport{}, errors.ErrIncorrectRequest
+	}
+	limitInt = capLimit(limitInt, s.pageMax)
+	return s.store.GetImport(ctx, importIdInt, status, limitInt, pageInt)
+}
+
+func (s *ImportService) newReader(format string, r io.Reader) (*songfile.Reader, error) {
+	if format != "" && !songfile.IsFormat(format) {
+		return nil, errors.ErrIncorrectRequest
+	}
+	reader, err := songfile.NewReader(r, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errors.ErrIncorrectSongFile, err)
+	}
+	return reader, nil
+}
+
+// run reads rows in one goroutine, hands them to the enrichment workers and
+// saves what comes out in batches, in the order rows are done. The import
+// is finished as failed if reading or saving fails or ctx is done; rows
+// saved by then stay.
+func (s *ImportService) run(ctx context.Context, songImport entities.SongImport, reader *songfile.Reader) (entities.SongImport, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Infow("started song import", "workers", s.workers, "batch", s.batch)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rows := make(chan entities.SongImportRow)
+	var readErr error
+	go func() {
+		defer close(rows)
+		readErr = s.readRows(ctx, reader, rows)
+	}()
+
+	done := make(chan entities.SongImportRow)
+	var workers sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for row := range rows {
+				if row.Details != nil {
+					s.prepareRow(ctx, &row)
+				}
+				// a row cut short by cancellation is not reported
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case done <- row:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	batch := make([]entities.SongImportRow, 0, s.batch)
+	save := func() error {
+		saved, err := s.store.SaveImportRows(ctx, songImport.ID, batch)
+		if err != nil {
+			return err
+		}
+		for _, row := range saved {
+			row.Details = nil
+			songImport.Rows = append(songImport.Rows, row)
+			songImport.Total++
+			switch row.Status {
+			case entities.RowInserted:
+				songImport.Inserted++
+			case entities.RowDuplicate:
+				songImport.Duplicates++
+			case entities.RowEnrichmentFailed:
+				songImport.EnrichmentFailed++
+			case entities.RowInvalid:
+				songImport.Invalid++
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+	var err error
+	for row := range done {
+		batch = append(batch, row)
+		if len(batch) < s.batch {
+			continue
+		}
+		if err = save(); err != nil {
+			cancel()
+			break
+		}
+	}
+	for range done {
+	}
+	if err == nil && ctx.Err() != nil {
+		err = errImportInterrupted
+	}
+	if err == nil && len(batch) > 0 {
+		err = save()
+	}
+	// unless ctx is done, done is closed only after the reader has
+	// returned, so readErr is set by now
+	if err == nil {
+		err = readErr
+	}
+	slices.SortFunc(songImport.Rows, func(a, b entities.SongImportRow) int {
+		return a.Line - b.Line
+	})
+
+	songImport.Status = entities.SongImportDone
+	if err != nil {
+		songImport.Status = entities.SongImportFailed
+		songImport.Error = err.Error()
+		log.Errorw("error with importing songs", zap.Error(err))
+	}
+	finishedAt := time.Now()
+	songImport.FinishedAt = &finishedAt
+	if finishErr := s.store.FinishImport(context.WithoutCancel(ctx), songImport.ID, songImport.Status, songImport.Error); finishErr != nil && err == nil {
+		err = finishErr
+	}
+	log.Infow("song import is finished", "status", songImport.Status, "total", songImport.Total,
+		"inserted", songImport.Inserted, "duplicates", songImport.Duplicates,
+		"enrichmentFailed", songImport.EnrichmentFailed, "invalid", songImport.Invalid)
+	return songImport, err
+}
+
+// readRows sends every row of reader to rows. Rows that cannot be read are
+// invalid, and a song met before in the file is a duplicate without going
+// any further; the others carry the song in Details.
+func (s *ImportService) readRows(ctx context.Context, reader *songfile.Reader, rows chan<- entities.SongImportRow) error {
+	seen := make(map[string]int)
+	for {
+		song, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		row := entities.SongImportRow{
+			Line:      reader.Line(),
+			GroupName: song.GroupName,
+			Song:      song.Song,
+		}
+		var rowErr *songfile.RowError
+		if stdErrors.As(err, &rowErr) {
+			row.Status = entities.RowInvalid
+			row.Error = rowErr.Err.Error()
+		} else if err != nil {
+			return fmt.Errorf("error with reading song file: %w", err)
+		} else if line, ok := seen[store.SongNameKey(song.GroupName, song.Song)]; ok {
+			row.Status = entities.RowDuplicate
+			row.Error = fmt.Sprintf("repeats line %d", line)
+		} else {
+			seen[store.SongNameKey(song.GroupName, song.Song)] = row.Line
+			row.Details = &song
+		}
+		select {
+		case rows <- row:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// prepareRow readies the song of row for insertion. A song that exists
+// already makes the row a duplicate. Fields the file leaves empty are
+// filled by enrichment, and a row enrichment fails for is not inserted.
+// Fields the file sets are marked manual, so refreshes keep them.
+func (s *ImportService) prepareRow(ctx context.Context, row *entities.SongImportRow) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("line", row.Line, "group", row.GroupName, "song", row.Song)
+	existing, err := s.songs.FindSong(ctx, row.GroupName, row.Song)
+	if err == nil {
+		row.Status = entities.RowDuplicate
+		row.SongID = &existing.ID
+		row.Details = nil
+		return
+	}
+	if !stdErrors.Is(err, errors.ErrSongNotFound) {
+		// the batch looks the song up again before inserting it
+		log.Warnw("error with finding song", zap.Error(err))
+	}
+
+	song := row.Details
+	sources := make(entities.FieldSources)
+	for field, set := range map[string]bool{
+		enrichment.FieldReleaseDate: !song.ReleaseDate.IsZero(),
+		enrichment.FieldText:        song.Text != "",
+		enrichment.FieldLink:        song.Link != "",
+	} {
+		if set {
+			sources[field] = entities.SourceManual
+		}
+	}
+	if len(sources) < 3 {
+		enriched, err := s.enricher.Enrich(logger.ContextWithLogger(ctx, log), song.GroupName, song.Song)
+		if err != nil {
+			log.Warnw("error with enriching imported song", zap.Error(err))
+			row.Status = entities.RowEnrichmentFailed
+			row.Error = err.Error()
+			row.Details = nil
+			return
+		}
+		if song.ReleaseDate.IsZero() && !enriched.Song.ReleaseDate.IsZero() {
+			song.ReleaseDate = enriched.Song.ReleaseDate
+			sources[enrichment.FieldReleaseDate] = enriched.Sources[enrichment.FieldReleaseDate]
+		}
+		if song.Text == "" && enriched.Song.Text != "" {
+			song.Text = enriched.Song.Text
+			sources[enrichment.FieldText] = enriched.Sources[enrichment.FieldText]
+		}
+		if song.Link == "" && enriched.Song.Link != "" {
+			song.Link = enriched.Song.Link
+			sources[enrichment.FieldLink] = enriched.Sources[enrichment.FieldLink]
+		}
+		if song.Album == nil {
+			song.Album = enriched.Song.Album
+		}
+	}
+	enrichedAt := time.Now()
+	song.Status = entities.SongStatusEnriched
+	song.FieldSources = sources
+	song.EnrichedAt = &enrichedAt
+}
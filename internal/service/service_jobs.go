@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/store"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+type JobService struct {
+	store store.Jobs
+}
+
+func NewJobService(store store.Jobs) *JobService {
+	return &JobService{
+		store: store,
+	}
+}
+
+func (s *JobService) GetJob(ctx context.Context, jobId string) (entities.EnrichmentJob, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("jobId", jobId)
+	jobIdInt, err := strconv.Atoi(jobId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.EnrichmentJob{}, errors.ErrIncorrectRequest
+	}
+	return s.store.GetJob(ctx, jobIdInt)
+}
+
+func (s *JobService) RetryJob(ctx context.Context, jobId string) error {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("jobId", jobId)
+	jobIdInt, err := strconv.Atoi(jobId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return errors.ErrIncorrectRequest
+	}
+	return s.store.RetryJob(ctx, jobIdInt)
+}
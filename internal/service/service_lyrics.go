@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/lyrics"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ImportLRC replaces the song text with the lines of the LRC file and stores
+// their timestamps and offset.
+func (s *SongService) ImportLRC(ctx context.Context, songId, data string) error {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("songId", songId)
+	songIdInt, err := strconv.Atoi(songId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return errors.ErrIncorrectRequest
+	}
+	lrc, err := lyrics.ParseLRC(data)
+	if err != nil {
+		log.Errorw("error with parsing lrc", zap.Error(err))
+		return errors.ErrIncorrectRequest
+	}
+	if len(lrc.Lines) == 0 {
+		log.Errorw("lrc has no lines")
+		return errors.ErrIncorrectRequest
+	}
+
+	timings := make([]entities.LyricsTiming, 0, len(lrc.Lines))
+	for i, line := range lrc.Lines {
+		if !line.Timed {
+			continue
+		}
+		timings = append(timings, entities.LyricsTiming{
+			SongID: uint(songIdInt),
+			Line:   i + 1,
+			TimeMs: int(line.Time.Milliseconds()),
+		})
+	}
+	return s.store.ImportSyncedLyrics(ctx, songIdInt, lrc.Text(), int(lrc.Offset.Milliseconds()), timings)
+}
+
+// ExportLRC formats the song's synced lyrics as LRC with [ar:] and [ti:]
+// tags taken from the song.
+func (s *SongService) ExportLRC(ctx context.Context, songId string) (string, error) {
+	lrc, err := s.syncedLyrics(ctx, songId)
+	if err != nil {
+		return "", err
+	}
+	return lrc.String(), nil
+}
+
+// GetActiveLine returns the lyrics line sung at the playback position.
+func (s *SongService) GetActiveLine(ctx context.Context, songId, position string) (entities.ActiveLineResponse, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("songId", songId, "position", position)
+	positionDuration, err := lyrics.ParsePosition(position)
+	if err != nil {
+		log.Errorw("error with parsing position", zap.Error(err))
+		return entities.ActiveLineResponse{}, errors.ErrIncorrectRequest
+	}
+	lrc, err := s.syncedLyrics(ctx, songId)
+	if err != nil {
+		return entities.ActiveLineResponse{}, err
+	}
+
+	var result entities.ActiveLineResponse
+	index, ok := lrc.ActiveLine(positionDuration)
+	if ok {
+		result.Active = true
+		result.Line = index + 1
+		result.Text = lrc.Lines[index].Text
+		result.Time = lyrics.FormatTimestamp(lrc.Lines[index].Time - lrc.Offset)
+	}
+	for _, line := range lrc.Lines[index+1:] {
+		if line.Timed {
+			result.NextTime = lyrics.FormatTimestamp(line.Time - lrc.Offset)
+			break
+		}
+	}
+	return result, nil
+}
+
+func (s *SongService) syncedLyrics(ctx context.Context, songId string) (lyrics.LRC, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("songId", songId)
+	songIdInt, err := strconv.Atoi(songId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return lyrics.LRC{}, errors.ErrIncorrectRequest
+	}
+	song, timings, err := s.store.GetSyncedLyrics(ctx, songIdInt)
+	if err != nil {
+		return lyrics.LRC{}, err
+	}
+	if len(timings) == 0 {
+		log.Errorw("song has no synced lyrics")
+		return lyrics.LRC{}, errors.ErrNoSyncedLyrics
+	}
+
+	lrc := lyrics.LRC{
+		Artist: song.GroupName,
+		Title:  song.Song,
+		Offset: time.Duration(song.LyricsOffsetMs) * time.Millisecond,
+	}
+	byLine := make(map[int]int, len(timings))
+	for _, timing := range timings {
+		byLine[timing.Line] = timing.TimeMs
+	}
+	for i, text := range strings.Split(song.Text, "\n") {
+		line := lyrics.SyncedLine{Text: text}
+		if timeMs, ok := byLine[i+1]; ok {
+			line.Timed = true
+			line.Time = time.Duration(timeMs) * time.Millisecond
+		}
+		lrc.Lines = append(lrc.Lines, line)
+	}
+	return lrc, nil
+}
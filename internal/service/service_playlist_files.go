@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/playlistfile"
+	stdErrors "errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// defaultImportName names imported playlists whose file has no title.
+const defaultImportName = "Imported playlist"
+
+func (s *PlaylistService) ExportPlaylist(ctx context.Context, playlistId, format string) (entities.PlaylistFile, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("playlistId", playlistId, "format", format)
+	format, err := exportFormat(format)
+	if err != nil {
+		log.Errorw("unsupported playlist format")
+		return entities.PlaylistFile{}, err
+	}
+	playlistIdInt, err := strconv.Atoi(playlistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.PlaylistFile{}, errors.ErrIncorrectRequest
+	}
+	playlist, err := s.store.GetPlaylist(ctx, playlistIdInt)
+	if err != nil {
+		return entities.PlaylistFile{}, err
+	}
+	tracks := make([]playlistfile.Track, 0, len(playlist.Entries))
+	for _, entry := range playlist.Entries {
+		tracks = append(tracks, playlistfile.Track{
+			Group:    entry.GroupName,
+			Song:     entry.Song,
+			Location: entry.Link,
+		})
+	}
+	return playlistFile("playlist-"+playlistId, format, playlist.Name, tracks)
+}
+
+// ImportPlaylist makes a playlist of an m3u, m3u8 or xspf file; an empty
+// format is guessed from the data. Each track is matched to a song by group
+// and song name; songs that are not found are inserted like insertsong
+// does, with enrichment. Tracks that can be neither matched nor inserted
+// are reported and left out. The playlist is named name, or after the
+// file title.
+func (s *PlaylistService) ImportPlaylist(ctx context.Context, name, format string, data []byte) (entities.PlaylistImportResponse, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("name", name, "format", format)
+	if format != "" && !playlistfile.IsFormat(format) {
+		log.Errorw("unsupported playlist format")
+		return entities.PlaylistImportResponse{}, errors.ErrIncorrectRequest
+	}
+	title, tracks, err := playlistfile.Parse(data, format)
+	if err != nil {
+		log.Errorw("error with parsing playlist file", zap.Error(err))
+		return entities.PlaylistImportResponse{}, fmt.Errorf("%w: %w", errors.ErrIncorrectFile, err)
+	}
+	if s.importMax > 0 && len(tracks) > s.importMax {
+		log.Errorw("playlist file is too long", "tracks", len(tracks))
+		return entities.PlaylistImportResponse{}, fmt.Errorf("%w: more than %d tracks", errors.ErrIncorrectFile, s.importMax)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = title
+	}
+	if name == "" {
+		name = defaultImportName
+	}
+
+	result := entities.PlaylistImportResponse{
+		Tracks: make([]entities.ImportedTrack, 0, len(tracks)),
+	}
+	entries := make([]entities.PlaylistEntry, 0, len(tracks))
+	for _, track := range tracks {
+		imported := s.importTrack(ctx, track)
+		result.Tracks = append(result.Tracks, imported)
+		if imported.Status != entities.ImportFailed {
+			entries = append(entries, entities.PlaylistEntry{SongID: imported.SongID})
+		}
+	}
+	result.Playlist, err = s.store.InsertPlaylist(ctx, entities.Playlist{
+		Name:    name,
+		Entries: entries,
+	})
+	if err != nil {
+		return entities.PlaylistImportResponse{}, err
+	}
+	log.Infow("playlist is imported", "playlistId", result.Playlist.ID, "tracks", len(tracks), "entries", len(entries))
+	return result, nil
+}
+
+// importTrack finds the song of track or inserts it.
+func (s *PlaylistService) importTrack(ctx context.Context, track playlistfile.Track) entities.ImportedTrack {
+	log := logger.LoggerFromContext(ctx)
+	imported := entities.ImportedTrack{
+		Group:    track.Group,
+		Song:     track.Song,
+		Location: track.Location,
+		Status:   entities.ImportFailed,
+	}
+	if track.Group == "" || track.Song == "" {
+		imported.Error = "group or song name is missing"
+		return imported
+	}
+	song, err := s.songStore.FindSong(ctx, track.Group, track.Song)
+	if err == nil {
+		imported.SongID = song.ID
+		imported.Status = entities.ImportMatched
+		return imported
+	}
+	if !stdErrors.Is(err, errors.ErrSongNotFound) {
+		imported.Error = "error with finding song"
+		return imported
+	}
+	inserted, err := s.songs.InsertSong(ctx, entities.SongRequest{
+		Group: track.Group,
+		Song:  track.Song,
+	})
+	var exists *errors.SongExistsError
+	if stdErrors.As(err, &exists) {
+		// inserted meanwhile by someone else
+		imported.SongID = exists.ID
+		imported.Status = entities.ImportMatched
+		return imported
+	}
+	if err != nil {
+		log.Warnw("error with inserting imported song", "group", track.Group, "song", track.Song, zap.Error(err))
+		imported.Error = err.Error()
+		return imported
+	}
+	imported.SongID = uint(inserted.ID)
+	imported.Status = entities.ImportCreated
+	return imported
+}
+
+// exportFormat defaults the export format to m3u8.
+func exportFormat(format string) (string, error) {
+	if format == "" {
+		return playlistfile.FormatM3U8, nil
+	}
+	if !playlistfile.IsFormat(format) {
+		return "", errors.ErrIncorrectRequest
+	}
+	return format, nil
+}
+
+func playlistFile(name, format, title string, tracks []playlistfile.Track) (entities.PlaylistFile, error) {
+	var data bytes.Buffer
+	if err := playlistfile.Write(&data, format, title, tracks); err != nil {
+		return entities.PlaylistFile{}, err
+	}
+	return entities.PlaylistFile{
+		Name:        name + "." + format,
+		ContentType: playlistfile.ContentType(format),
+		Data:        data.Bytes(),
+	}, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/store"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type PlaylistService struct {
+	store        store.Playlists
+	songStore    store.Songs
+	songs        Songs
+	pageMaxLimit int
+	importMax    int
+}
+
+// NewPlaylistService creates the playlist service. Imported songs that are
+// not found are inserted through songs, as if posted to insertsong.
+func NewPlaylistService(store store.Playlists, songStore store.Songs, songs Songs, cfg config.Config) *PlaylistService {
+	return &PlaylistService{
+		store:        store,
+		songStore:    songStore,
+		songs:        songs,
+		pageMaxLimit: cfg.PageMaxLimit,
+		importMax:    cfg.PlaylistImportMax,
+	}
+}
+
+func (s *PlaylistService) InsertPlaylist(ctx context.Context, req entities.PlaylistRequest) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("name", req.Name)
+	if strings.TrimSpace(req.Name) == "" {
+		log.Errorw("playlist name is empty")
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	return s.store.InsertPlaylist(ctx, entities.Playlist{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	})
+}
+
+func (s *PlaylistService) GetPlaylists(ctx context.Context, limit, page string) (entities.PlaylistsPage, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("limit", limit, "page", page)
+	limitInt, pageInt, err := parsePage(limit, page)
+	if err != nil {
+		log.Errorw("error with parsing page", zap.Error(err))
+		return entities.PlaylistsPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt = capLimit(limitInt, s.pageMaxLimit)
+	playlists, total, err := s.store.GetPlaylists(ctx, limitInt, pageInt)
+	if err != nil {
+		return entities.PlaylistsPage{}, err
+	}
+	if playlists == nil {
+		playlists = []entities.Playlist{}
+	}
+	return entities.PlaylistsPage{
+		Items: playlists,
+		Total: total,
+		Page:  pageInt,
+		Limit: limitInt,
+	}, nil
+}
+
+func (s *PlaylistService) GetPlaylist(ctx context.Context, playlistId string) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("playlistId", playlistId)
+	playlistIdInt, err := strconv.Atoi(playlistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	return s.store.GetPlaylist(ctx, playlistIdInt)
+}
+
+func (s *PlaylistService) UpdatePlaylist(ctx context.Context, playlistId string, update entities.PlaylistUpdate) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("playlistId", playlistId)
+	playlistIdInt, err := strconv.Atoi(playlistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			log.Errorw("playlist name is empty")
+			return entities.Playlist{}, errors.ErrIncorrectRequest
+		}
+		update.Name = &name
+	}
+	return s.store.UpdatePlaylist(ctx, playlistIdInt, update)
+}
+
+// DeletePlaylist deletes the playlist; version, if not empty, must be its
+// current version.
+func (s *PlaylistService) DeletePlaylist(ctx context.Context, playlistId, version string) error {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("playlistId", playlistId, "version", version)
+	playlistIdInt, err := strconv.Atoi(playlistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return errors.ErrIncorrectRequest
+	}
+	versionInt, err := parseVersion(version)
+	if err != nil {
+		log.Errorw("error with converting version to int", zap.Error(err))
+		return errors.ErrIncorrectRequest
+	}
+	return s.store.DeletePlaylist(ctx, playlistIdInt, versionInt)
+}
+
+func (s *PlaylistService) AddEntry(ctx context.Context, playlistId string, req entities.PlaylistEntryRequest) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("playlistId", playlistId, "songId", req.SongID, "position", req.Position)
+	playlistIdInt, err := strconv.Atoi(playlistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	if req.SongID == 0 || req.Position < 0 {
+		log.Errorw("incorrect song id or position")
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	return s.store.AddEntry(ctx, playlistIdInt, req.SongID, req.Position, req.Version)
+}
+
+func (s *PlaylistService) MoveEntry(ctx context.Context, playlistId, entryId string, req entities.PlaylistMoveRequest) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("playlistId", playlistId, "entryId", entryId, "position", req.Position)
+	playlistIdInt, err := strconv.Atoi(playlistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	entryIdInt, err := strconv.Atoi(entryId)
+	if err != nil || entryIdInt < 1 {
+		log.Errorw("error with converting entry id to int", zap.Error(err))
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	if req.Position < 1 {
+		log.Errorw("incorrect position")
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	return s.store.MoveEntry(ctx, playlistIdInt, uint(entryIdInt), req.Position, req.Version)
+}
+
+// RemoveEntry removes the entry; version, if not empty, must be the current
+// version of the playlist.
+func (s *PlaylistService) RemoveEntry(ctx context.Context, playlistId, entryId, version string) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("playlistId", playlistId, "entryId", entryId, "version", version)
+	playlistIdInt, err := strconv.Atoi(playlistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	entryIdInt, err := strconv.Atoi(entryId)
+	if err != nil || entryIdInt < 1 {
+		log.Errorw("error with converting entry id to int", zap.Error(err))
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	versionInt, err := parseVersion(version)
+	if err != nil {
+		log.Errorw("error with converting version to int", zap.Error(err))
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	return s.store.RemoveEntry(ctx, playlistIdInt, uint(entryIdInt), versionInt)
+}
+
+func (s *PlaylistService) ReorderPlaylist(ctx context.Context, playlistId string, req entities.PlaylistOrderRequest) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("playlistId", playlistId)
+	playlistIdInt, err := strconv.Atoi(playlistId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Playlist{}, errors.ErrIncorrectRequest
+	}
+	return s.store.ReorderPlaylist(ctx, playlistIdInt, req.EntryIDs, req.Version)
+}
+
+// parseVersion parses an optional playlist version; empty is no version.
+func parseVersion(version string) (*int, error) {
+	if version == "" {
+		return nil, nil
+	}
+	versionInt, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, err
+	}
+	return &versionInt, nil
+}
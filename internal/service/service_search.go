@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+)
+
+const (
+	searchLangAuto    = "auto"
+	searchLangEnglish = "english"
+	searchLangRussian = "russian"
+)
+
+// SearchLyrics runs a ranked full-text search. With lang=auto the query is
+// matched with both the english and russian stemmers and snippets use the
+// language of the query's script.
+func (s *SongService) SearchLyrics(ctx context.Context, query, lang, limit, page string) (entities.SearchPage, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("query", query, "lang", lang, "limit", limit, "page", page)
+	query = strings.TrimSpace(query)
+	if query == "" {
+		log.Errorw("search query is empty")
+		return entities.SearchPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt, pageInt, err := parsePage(limit, page)
+	if err != nil {
+		log.Errorw("error with parsing page", zap.Error(err))
+		return entities.SearchPage{}, errors.ErrIncorrectRequest
+	}
+
+	var languages []string
+	headlineConfig := lang
+	switch lang {
+	case "", searchLangAuto:
+		languages = []string{searchLangEnglish, searchLangRussian}
+		headlineConfig = detectLanguage(query)
+	case searchLangEnglish, searchLangRussian:
+		languages = []string{lang}
+	default:
+		log.Errorw("unsupported search language")
+		return entities.SearchPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt = s.capLimit(limitInt)
+	results, total, err := s.store.SearchLyrics(ctx, query, languages, headlineConfig, limitInt, pageInt)
+	if err != nil {
+		return entities.SearchPage{}, err
+	}
+	return entities.SearchPage{
+		Items: results,
+		Total: total,
+		Page:  pageInt,
+		Limit: limitInt,
+	}, nil
+}
+
+// FuzzySearch finds songs by group and/or song name tolerating typos, case
+// and accents. threshold overrides the configured minimal similarity.
+func (s *SongService) FuzzySearch(ctx context.Context, query, field, threshold, limit, page string) (entities.FuzzyPage, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("query", query, "field", field, "threshold", threshold, "limit", limit, "page", page)
+	query = strings.TrimSpace(query)
+	if query == "" {
+		log.Errorw("search query is empty")
+		return entities.FuzzyPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt, pageInt, err := parsePage(limit, page)
+	if err != nil {
+		log.Errorw("error with parsing page", zap.Error(err))
+		return entities.FuzzyPage{}, errors.ErrIncorrectRequest
+	}
+
+	thresholdFloat, err := parseThreshold(threshold, s.fuzzyThreshold)
+	if err != nil {
+		log.Errorw("error with parsing threshold", zap.Error(err))
+		return entities.FuzzyPage{}, errors.ErrIncorrectRequest
+	}
+
+	var fields []string
+	switch field {
+	case "", "all":
+		fields = []string{"group", "song"}
+	case "group", "song":
+		fields = []string{field}
+	default:
+		log.Errorw("unsupported search field")
+		return entities.FuzzyPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt = s.capLimit(limitInt)
+	results, total, err := s.store.FuzzySearch(ctx, query, fields, thresholdFloat, limitInt, pageInt)
+	if err != nil {
+		return entities.FuzzyPage{}, err
+	}
+	return entities.FuzzyPage{
+		Items: results,
+		Total: total,
+		Page:  pageInt,
+		Limit: limitInt,
+	}, nil
+}
+
+// parseThreshold parses a similarity threshold param, between 0 and 1;
+// empty is defaultThreshold.
+func parseThreshold(threshold string, defaultThreshold float64) (float64, error) {
+	if threshold == "" {
+		return defaultThreshold, nil
+	}
+	thresholdFloat, err := strconv.ParseFloat(threshold, 64)
+	if err != nil {
+		return 0, err
+	}
+	if thresholdFloat < 0 || thresholdFloat > 1 {
+		return 0, fmt.Errorf("threshold %v is out of [0, 1]", thresholdFloat)
+	}
+	return thresholdFloat, nil
+}
+
+// detectLanguage picks russian for queries written mostly in Cyrillic.
+func detectLanguage(query string) string {
+	cyrillic, latin := 0, 0
+	for _, r := range query {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if cyrillic > latin {
+		return searchLangRussian
+	}
+	return searchLangEnglish
+}
+
+// parsePage parses limit and page query params with the defaults GetSongs
+// uses: 10 items, first page.
+func parsePage(limit, page string) (int, int, error) {
+	if limit == "" || limit == "0" {
+		limit = "10"
+	}
+	if page == "" || page == "0" {
+		page = "1"
+	}
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		return 0, 0, err
+	}
+	pageInt, err := strconv.Atoi(page)
+	if err != nil {
+		return 0, 0, err
+	}
+	if limitInt < 1 || pageInt < 1 {
+		return 0, 0, errors.ErrIncorrectRequest
+	}
+	// the stores skip (page-1)*limit rows; an offset that overflows turns
+	// negative and is ignored
+	if pageInt-1 > math.MaxInt/limitInt {
+		return 0, 0, errors.ErrIncorrectRequest
+	}
+	return limitInt, pageInt, nil
+}
@@ -2,13 +2,15 @@ package service
 
 import (
 	"context"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/enrichment"
 	"effectiveMobile/internal/entities"
 	"effectiveMobile/internal/errors"
 	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/lyrics"
 	"effectiveMobile/internal/store"
-	"encoding/json"
-	"fmt"
-	"net/http"
+	stdErrors "errors"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -17,94 +19,266 @@ import (
 )
 
 type SongService struct {
-	store      store.Songs
-	songApiUrl string
-	httpClient http.Client
+	store              store.Songs
+	jobs               store.Jobs
+	enricher           *enrichment.Chain
+	outboundCtx        context.Context
+	asyncEnrichment    bool
+	maxAttempts        int
+	fuzzyThreshold     float64
+	duplicateThreshold float64
+	pageMaxLimit       int
 }
 
-func NewSongService(store store.Songs, songApiUrl string, apiTimeout int) *SongService {
-	client := http.Client{
-		Timeout: time.Duration(apiTimeout) * time.Second,
-	}
+func NewSongService(outboundCtx context.Context, store store.Songs, jobs store.Jobs, enricher *enrichment.Chain, cfg config.Config) *SongService {
 	return &SongService{
-		store:      store,
-		songApiUrl: songApiUrl,
-		httpClient: client,
+		store:              store,
+		jobs:               jobs,
+		enricher:           enricher,
+		outboundCtx:        outboundCtx,
+		asyncEnrichment:    cfg.EnrichmentMode == config.EnrichmentModeAsync,
+		maxAttempts:        cfg.EnrichmentMaxAttempts,
+		fuzzyThreshold:     cfg.FuzzyThreshold,
+		duplicateThreshold: cfg.DuplicateThreshold,
+		pageMaxLimit:       cfg.PageMaxLimit,
 	}
 }
 
-func (s *SongService) InsertSong(ctx context.Context, insertReq entities.SongRequest) (int, error) {
+// InsertSong stores the song. In sync mode the song api is queried first and
+// its failure fails the insert; in async mode the song is stored at once as
+// pending and an enrichment job is queued for the worker pool.
+func (s *SongService) InsertSong(ctx context.Context, insertReq entities.SongRequest) (entities.InsertResponse, error) {
 	log := logger.LoggerFromContext(ctx)
 	log = log.With("song", insertReq.Song, "group", insertReq.Group)
-	requestUrl := fmt.Sprintf("http://%s/info?group=%s&song=%s", s.songApiUrl, insertReq.Group, insertReq.Song)
-	request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
-	if err != nil {
-		log.Errorw("error with creating request", zap.Error(err))
-		return 0, err
-	}
-	log.Infow("doing request to song api", "requestUrl", requestUrl)
-	response, err := s.httpClient.Do(request)
-	if err != nil {
-		log.Errorw("error with sending request", zap.Error(err))
-		return 0, err
+	if s.asyncEnrichment {
+		song := entities.Song{
+			GroupName: insertReq.Group,
+			Song:      insertReq.Song,
+			Status:    entities.SongStatusPendingEnrichment,
+		}
+		id, jobId, err := s.jobs.InsertSongWithJob(ctx, song, s.maxAttempts)
+		if err != nil {
+			log.Errorw("error with inserting song", zap.Error(err))
+			return entities.InsertResponse{}, err
+		}
+		log.Infow("song is inserted, enrichment is queued", "jobId", jobId)
+		return entities.InsertResponse{
+			ID:     id,
+			Status: entities.SongStatusPendingEnrichment,
+			JobID:  jobId,
+		}, nil
 	}
 
-	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		log.Errorw("error with sending request", zap.Error(err))
-		return 0, errors.ErrAnotherStatucCode
-	}
+	requestCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(s.outboundCtx, cancel)
+	defer stop()
 
-	var songDetail entities.Song
-	err = json.NewDecoder(response.Body).Decode(&songDetail)
+	enriched, err := s.enricher.Enrich(logger.ContextWithLogger(requestCtx, log), insertReq.Group, insertReq.Song)
 	if err != nil {
-		log.Errorw("error with decoding response", zap.Error(err))
-		return 0, err
+		log.Errorw("error with getting song info", zap.Error(err))
+		return entities.InsertResponse{}, err
 	}
 
+	enrichedAt := time.Now()
+	songDetail := enriched.Song
 	songDetail.GroupName = insertReq.Group
 	songDetail.Song = insertReq.Song
+	songDetail.Status = entities.SongStatusEnriched
+	songDetail.FieldSources = enriched.Sources
+	songDetail.EnrichedAt = &enrichedAt
 	log.Info("song is received")
 	id, err := s.store.InsertSong(ctx, songDetail)
 	if err != nil {
 		log.Errorw("error with inserting song", zap.Error(err))
-		return 0, err
+		return entities.InsertResponse{}, err
 	}
 
 	log.Info("song is inserted")
-	return id, nil
+	return entities.InsertResponse{
+		ID:      id,
+		Status:  entities.SongStatusEnriched,
+		Sources: enriched.Sources,
+	}, nil
 }
 
-func (s *SongService) GetSongs(ctx context.Context, filters entities.Song, limit, offset string) ([]entities.Song, error) {
+// Values of the count param of song listings.
+const (
+	CountExact    = "exact"
+	CountEstimate = "estimate"
+	CountNone     = "none"
+)
+
+// GetSongs lists songs with page/limit pagination in sort order, see
+// parseSort; by id when sort is empty. count chooses how the total is
+// computed, CountExact by default.
+func (s *SongService) GetSongs(ctx context.Context, params entities.SongFilterParams, sort, limit, offset, count string) (entities.SongsPage, error) {
 	log := logger.LoggerFromContext(ctx)
-	log = log.With("filters", filters, "limit", limit, "offset", offset)
-	if limit == "" {
-		limit = "10"
+	log = log.With("filters", params, "sort", sort, "limit", limit, "offset", offset, "count", count)
+	filters, err := parseFilters(params)
+	if err != nil {
+		log.Errorw("error with parsing filters", zap.Error(err))
+		if stdErrors.Is(err, errors.ErrIncorrectFilter) {
+			return entities.SongsPage{}, err
+		}
+		return entities.SongsPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt, offsetInt, err := parsePage(limit, offset)
+	if err != nil {
+		log.Errorw("error with parsing page", zap.Error(err))
+		return entities.SongsPage{}, errors.ErrIncorrectRequest
+	}
+	if count == "" {
+		count = CountExact
+	}
+	if !validCount(count) {
+		log.Errorw("unsupported count")
+		return entities.SongsPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt = s.capLimit(limitInt)
+	sortKeys, err := parseSort(sort)
+	if err != nil {
+		log.Errorw("error with parsing sort", zap.Error(err))
+		return entities.SongsPage{}, err
+	}
+
+	songs, err := s.store.GetSongs(ctx, filters, sortKeys, limitInt, offsetInt)
+	if err != nil {
+		return entities.SongsPage{}, err
+	}
+	page := entities.SongsPage{
+		Items: songs,
+		Page:  offsetInt,
+		Limit: limitInt,
+	}
+	if err := s.countSongs(ctx, filters, count, &page); err != nil {
+		return entities.SongsPage{}, err
+	}
+	return page, nil
+}
+
+// GetSongsPage lists songs with keyset pagination. An empty cursor starts
+// from the first song; otherwise it must be a cursor of a previous page
+// requested with the same filters. The cursor remembers its sort, so sort
+// may be omitted with a cursor but must not differ from it.
+// count is CountNone by default, since counting defeats the purpose of
+// keyset pagination on big tables.
+func (s *SongService) GetSongsPage(ctx context.Context, params entities.SongFilterParams, sort, limit, cursor, count string) (entities.SongsPage, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("filters", params, "sort", sort, "limit", limit, "cursor", cursor, "count", count)
+	limitInt, _, err := parsePage(limit, "")
+	if err != nil {
+		log.Errorw("error with parsing limit", zap.Error(err))
+		return entities.SongsPage{}, errors.ErrIncorrectRequest
+	}
+	limitInt = s.capLimit(limitInt)
+	filters, err := parseFilters(params)
+	if err != nil {
+		log.Errorw("error with parsing filters", zap.Error(err))
+		if stdErrors.Is(err, errors.ErrIncorrectFilter) {
+			return entities.SongsPage{}, err
+		}
+		return entities.SongsPage{}, errors.ErrIncorrectRequest
 	}
-	if offset == "" {
-		offset = "1"
+	if count == "" {
+		count = CountNone
 	}
-	if filters.ReleaseDate != "" {
-		_, err := time.Parse("02.01.2006", filters.ReleaseDate)
+	if !validCount(count) {
+		log.Errorw("unsupported count")
+		return entities.SongsPage{}, errors.ErrIncorrectRequest
+	}
+
+	var position *entities.SongCursor
+	if cursor != "" {
+		decoded, err := decodeCursor(cursor)
 		if err != nil {
-			log.Errorw("error with parsing release date", zap.Error(err))
-			return nil, errors.ErrIncorrectRequest
+			log.Errorw("error with decoding cursor", zap.Error(err))
+			return entities.SongsPage{}, errors.ErrIncorrectRequest
 		}
+		position = &decoded
 	}
-	limitInt, err := strconv.Atoi(limit)
+	sortKeys, err := parseSort(sort)
 	if err != nil {
-		log.Errorw("error with converting limit to int", zap.Error(err))
-		return nil, errors.ErrIncorrectRequest
+		log.Errorw("error with parsing sort", zap.Error(err))
+		return entities.SongsPage{}, err
+	}
+	if position != nil {
+		if sort != "" && formatSort(sortKeys) != position.Sort {
+			log.Errorw("sort differs from the cursor sort", "cursorSort", position.Sort)
+			return entities.SongsPage{}, errors.ErrIncorrectRequest
+		}
+		sortKeys, err = parseSort(position.Sort)
+		if err != nil {
+			log.Errorw("error with parsing cursor sort", zap.Error(err))
+			return entities.SongsPage{}, err
+		}
 	}
-	offsetInt, err := strconv.Atoi(offset)
+
+	songs, more, err := s.store.GetSongsPage(ctx, filters, sortKeys, position, limitInt)
 	if err != nil {
-		log.Errorw("error with converting offset to int", zap.Error(err))
-		return nil, errors.ErrIncorrectRequest
+		log.Errorw("error with getting songs page", zap.Error(err))
+		return entities.SongsPage{}, err
+	}
+
+	page := entities.SongsPage{
+		Items: songs,
+		Limit: limitInt,
+	}
+	if err := s.countSongs(ctx, filters, count, &page); err != nil {
+		return entities.SongsPage{}, err
+	}
+	if len(songs) == 0 {
+		return page, nil
+	}
+	// the page a cursor came from always exists, the one beyond it only
+	// when the store found more rows
+	backward := position != nil && position.Backward
+	if more || backward {
+		page.NextCursor = encodeCursor(entities.SongCursor{
+			Sort:   formatSort(sortKeys),
+			Values: store.SortValues(songs[len(songs)-1], sortKeys),
+		})
 	}
-	if limitInt == 0 {
-		limitInt = 10
+	if (backward && more) || (!backward && position != nil) {
+		page.PrevCursor = encodeCursor(entities.SongCursor{
+			Sort:     formatSort(sortKeys),
+			Values:   store.SortValues(songs[0], sortKeys),
+			Backward: true,
+		})
+	}
+	log.Infow("songs page is got", "count", len(songs))
+	return page, nil
+}
+
+func validCount(count string) bool {
+	return count == CountExact || count == CountEstimate || count == CountNone
+}
+
+// countSongs fills the total of page as requested by count.
+func (s *SongService) countSongs(ctx context.Context, filters entities.SongFilters, count string, page *entities.SongsPage) error {
+	if count == CountNone {
+		return nil
+	}
+	total, estimated, err := s.store.CountSongs(ctx, filters, count == CountEstimate)
+	if err != nil {
+		return err
 	}
-	return s.store.GetSongs(ctx, filters, limitInt, offsetInt)
+	page.Total = &total
+	page.TotalEstimated = estimated
+	return nil
+}
+
+// capLimit bounds the page size by PAGE_MAX_LIMIT.
+func (s *SongService) capLimit(limit int) int {
+	return capLimit(limit, s.pageMaxLimit)
+}
+
+// capLimit bounds limit by maxLimit, if it is positive.
+func capLimit(limit, maxLimit int) int {
+	if maxLimit > 0 && limit > maxLimit {
+		return maxLimit
+	}
+	return limit
 }
 
 func (s *SongService) DeleteSong(ctx context.Context, songId string) error {
@@ -186,16 +360,83 @@ func (s *SongService) GetTextSong(ctx context.Context, lineInVerse, page, limit,
 	return strings.Join(result, "\n"), nil
 }
 
-func (s *SongService) UpdateSong(ctx context.Context, songId string, song entities.SongUpdate) error {
+// GetVerses pages the song text by stanzas, i.e. blank-line separated
+// blocks, limit stanzas per page.
+func (s *SongService) GetVerses(ctx context.Context, page, limit, songId string) (entities.VersesResponse, error) {
 	log := logger.LoggerFromContext(ctx)
 	log = log.With("songId", songId)
-	if song.ReleaseDate != nil {
-		_, err := time.Parse("02.01.2006", *song.ReleaseDate)
-		if err != nil {
-			log.Errorw("error with parsing release date", zap.Error(err))
-			return errors.ErrIncorrectRequest
+	songIdInt, err := strconv.Atoi(songId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.VersesResponse{}, errors.ErrIncorrectRequest
+	}
+	limitInt, pageInt, err := parsePage(limit, page)
+	if err != nil {
+		log.Errorw("error with parsing page", zap.Error(err))
+		return entities.VersesResponse{}, errors.ErrIncorrectRequest
+	}
+	limitInt = s.capLimit(limitInt)
+
+	fullText, err := s.store.GetTextSong(ctx, songIdInt)
+	if err != nil {
+		log.Errorw("error with getting song text", zap.Error(err))
+		return entities.VersesResponse{}, errors.ErrIncorrectRequest
+	}
+	stanzas := lyrics.SplitStanzas(fullText)
+
+	result := entities.VersesResponse{
+		Verses: []entities.Verse{},
+		Page:   pageInt,
+		Limit:  limitInt,
+		Total:  len(stanzas),
+	}
+	// the first page of a song without text is empty, pages past the end do
+	// not exist; comparing pages keeps huge ones from overflowing the index
+	if pageInt > 1 && pageInt > (len(stanzas)+limitInt-1)/limitInt {
+		log.Errorw("page is past the last stanza", "page", pageInt, "total", len(stanzas))
+		return entities.VersesResponse{}, errors.ErrIncorrectRequest
+	}
+	startIndex := (pageInt - 1) * limitInt
+	endIndex := min(startIndex+limitInt, len(stanzas))
+	for i := startIndex; i < endIndex; i++ {
+		result.Verses = append(result.Verses, entities.Verse{
+			Index:     i + 1,
+			StartLine: stanzas[i].StartLine,
+			EndLine:   stanzas[i].EndLine,
+			Lines:     stanzas[i].Lines,
+		})
+	}
+	return result, nil
+}
+
+// GetSections returns the lyrics sections of the song, optionally only of
+// one kind and number, e.g. kind=chorus or kind=verse&number=2.
+func (s *SongService) GetSections(ctx context.Context, songId, kind, number string) ([]entities.LyricsSection, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("songId", songId, "kind", kind, "number", number)
+	songIdInt, err := strconv.Atoi(songId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return nil, errors.ErrIncorrectRequest
+	}
+	if kind != "" && !slices.Contains(lyrics.Kinds, kind) {
+		log.Errorw("unknown section kind")
+		return nil, errors.ErrIncorrectRequest
+	}
+	numberInt := 0
+	if number != "" {
+		numberInt, err = strconv.Atoi(number)
+		if err != nil || numberInt < 1 {
+			log.Errorw("error with converting number to int", zap.Error(err))
+			return nil, errors.ErrIncorrectRequest
 		}
 	}
+	return s.store.GetSections(ctx, songIdInt, kind, numberInt)
+}
+
+func (s *SongService) UpdateSong(ctx context.Context, songId string, song entities.SongUpdate) error {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("songId", songId)
 	songIdInt, err := strconv.Atoi(songId)
 	if err != nil {
 		log.Errorw("error with converting id to int", zap.Error(err))
@@ -203,3 +444,39 @@ func (s *SongService) UpdateSong(ctx context.Context, songId string, song entiti
 	}
 	return s.store.UpdateSong(ctx, songIdInt, song)
 }
+
+// RefreshSong queries the enrichment providers again and merges the result
+// into the song. Fields edited by hand are kept.
+func (s *SongService) RefreshSong(ctx context.Context, songId string) (entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	log = log.With("songId", songId)
+	songIdInt, err := strconv.Atoi(songId)
+	if err != nil {
+		log.Errorw("error with converting id to int", zap.Error(err))
+		return entities.Song{}, errors.ErrIncorrectRequest
+	}
+	song, err := s.store.GetSong(ctx, songIdInt)
+	if err != nil {
+		log.Errorw("error with getting song", zap.Error(err))
+		return entities.Song{}, err
+	}
+
+	requestCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(s.outboundCtx, cancel)
+	defer stop()
+
+	return refreshSong(logger.ContextWithLogger(requestCtx, log), s.store, s.enricher, song)
+}
+
+func refreshSong(ctx context.Context, store store.Songs, enricher *enrichment.Chain, song entities.Song) (entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	enriched, err := enricher.Enrich(ctx, song.GroupName, song.Song)
+	if err != nil {
+		log.Errorw("error with getting song info", zap.Error(err))
+		return entities.Song{}, err
+	}
+	details := enriched.Song
+	details.FieldSources = enriched.Sources
+	return store.MergeEnrichment(ctx, int(song.ID), details)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/store"
+	"strings"
+)
+
+// parseSort parses a sort param like "releaseDate,-group,song": comma
+// separated json field names, each ascending or, with a "-" prefix,
+// descending. Fields are checked against the store allow-list.
+func parseSort(sort string) ([]entities.SortKey, error) {
+	if sort == "" {
+		return nil, nil
+	}
+	fields := strings.Split(sort, ",")
+	keys := make([]entities.SortKey, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		key := entities.SortKey{Field: strings.TrimPrefix(field, "-")}
+		key.Desc = key.Field != field
+		key.Field = strings.TrimPrefix(key.Field, "+")
+		if !store.IsSortable(key.Field) || seen[key.Field] {
+			return nil, errors.ErrIncorrectRequest
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// formatSort is the canonical form of keys, stored in cursors.
+func formatSort(keys []entities.SortKey) string {
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.Desc {
+			fields = append(fields, "-"+key.Field)
+		} else {
+			fields = append(fields, key.Field)
+		}
+	}
+	return strings.Join(fields, ",")
+}
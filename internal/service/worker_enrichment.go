@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"effectiveMobile/internal/config"
+	"effectiveMobile/internal/enrichment"
+	"effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/store"
+	stdErrors "errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EnrichmentWorker is a pool of workers that take queued enrichment jobs
+// from Postgres, run the enrichment chain and store the result. A failed job is
+// retried with exponential backoff until it runs out of attempts and is
+// moved to the dead-letter state.
+type EnrichmentWorker struct {
+	store        store.Jobs
+	enricher     *enrichment.Chain
+	workers      int
+	pollInterval time.Duration
+	retryBase    time.Duration
+	retryMax     time.Duration
+	lease        time.Duration
+}
+
+func NewEnrichmentWorker(store store.Jobs, enricher *enrichment.Chain, cfg config.Config) *EnrichmentWorker {
+	return &EnrichmentWorker{
+		store:        store,
+		enricher:     enricher,
+		workers:      cfg.EnrichmentWorkers,
+		pollInterval: time.Duration(cfg.EnrichmentPollInterval) * time.Second,
+		retryBase:    time.Duration(cfg.EnrichmentRetryBase) * time.Second,
+		retryMax:     time.Duration(cfg.EnrichmentRetryMax) * time.Second,
+		lease:        time.Duration(cfg.EnrichmentLease) * time.Second,
+	}
+}
+
+// Run starts the workers and blocks until ctx is done and all of them have
+// finished their current job.
+func (w *EnrichmentWorker) Run(ctx context.Context) {
+	log := logger.LoggerFromContext(ctx)
+	log.Infow("enrichment workers are started", "workers", w.workers)
+	var wg sync.WaitGroup
+	for i := 0; i < w.workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			w.loop(logger.ContextWithLogger(ctx, log.With("worker", worker)))
+		}(i)
+	}
+	wg.Wait()
+	log.Infow("enrichment workers are stopped")
+}
+
+func (w *EnrichmentWorker) loop(ctx context.Context) {
+	for {
+		processed := w.processNext(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if processed {
+			continue
+		}
+		timer := time.NewTimer(w.pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// processNext handles one due job and reports whether there was one.
+func (w *EnrichmentWorker) processNext(ctx context.Context) bool {
+	log := logger.LoggerFromContext(ctx)
+	job, song, err := w.store.ClaimJob(ctx, w.lease)
+	if stdErrors.Is(err, errors.ErrJobNotFound) {
+		return false
+	}
+	if err != nil {
+		return false
+	}
+
+	log = log.With("jobId", job.ID, "songId", song.ID, "attempt", job.Attempts)
+	jobCtx := logger.ContextWithLogger(ctx, log)
+	enriched, err := w.enricher.Enrich(jobCtx, song.GroupName, song.Song)
+	if err != nil {
+		// shutting down: hand the job back so another replica can take it
+		// without waiting for the lease to expire
+		if ctx.Err() != nil {
+			_ = w.store.RescheduleJob(context.WithoutCancel(jobCtx), job, "interrupted by shutdown", time.Now())
+			return true
+		}
+		log.Errorw("error with enriching song", zap.Error(err))
+		if job.Attempts >= job.MaxAttempts {
+			_ = w.store.BuryJob(jobCtx, job, err.Error())
+			return true
+		}
+		_ = w.store.RescheduleJob(jobCtx, job, err.Error(), time.Now().Add(w.backoff(job.Attempts)))
+		return true
+	}
+
+	details := enriched.Song
+	details.FieldSources = enriched.Sources
+	_ = w.store.CompleteJob(jobCtx, job, details)
+	return true
+}
+
+func (w *EnrichmentWorker) backoff(attempts int) time.Duration {
+	wait := w.retryBase << (attempts - 1)
+	if wait <= 0 || wait > w.retryMax {
+		wait = w.retryMax
+	}
+	// add up to 20% jitter so jobs that failed together spread out
+	return wait + time.Duration(rand.Int64N(int64(wait)/5+1))
+}
@@ -0,0 +1,260 @@
+// Package songfile reads and writes song catalogs as CSV or JSON Lines
+// (NDJSON), one song per row, and writes them as a json array too.
+//
+// A CSV file has a header row naming its columns: group and song are
+// required; releaseDate, text and link, and the album columns album,
+// albumReleaseDate, albumCover, disc and track are optional. An NDJSON line
+// is a song object with the same fields as the song json, the album being an
+// album object.
+package songfile
+
+import (
+	"bufio"
+	"bytes"
+	"effectiveMobile/internal/entities"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formats, named after their usual file extensions.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// IsFormat tells whether format is one of the supported formats.
+func IsFormat(format string) bool {
+	return format == FormatCSV || format == FormatNDJSON
+}
+
+// ErrNameMissing is the RowError of a row without the group or song name.
+var ErrNameMissing = errors.New("group or song name is missing")
+
+// RowError is a row that cannot be read. The Reader goes on with the next
+// row after it.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads songs row by row.
+type Reader struct {
+	format string
+	read   func() (entities.Song, error)
+	line   int
+}
+
+// NewReader starts reading r; an empty format is guessed from the first
+// byte: "{" is NDJSON, anything else CSV. A CSV header without the group or
+// song column is an error.
+func NewReader(r io.Reader, format string) (*Reader, error) {
+	buf := bufio.NewReader(r)
+	if format == "" {
+		format = detect(buf)
+	}
+	reader := &Reader{
+		format: format,
+	}
+	switch format {
+	case FormatCSV:
+		read, err := reader.csv(buf)
+		if err != nil {
+			return nil, err
+		}
+		reader.read = read
+	case FormatNDJSON:
+		reader.read = reader.ndjson(buf)
+	default:
+		return nil, fmt.Errorf("unsupported song file format %q", format)
+	}
+	return reader, nil
+}
+
+// Read returns the next song, io.EOF after the last one, or a *RowError for
+// a row that is malformed or lacks the group or song name. Other errors are
+// those of the underlying reader.
+func (r *Reader) Read() (entities.Song, error) {
+	song, err := r.read()
+	if err != nil {
+		return song, err
+	}
+	song.GroupName = strings.TrimSpace(song.GroupName)
+	song.Song = strings.TrimSpace(song.Song)
+	if song.GroupName == "" || song.Song == "" {
+		return song, &RowError{Line: r.line, Err: ErrNameMissing}
+	}
+	return song, nil
+}
+
+// Format is the format read, guessed if NewReader was given none.
+func (r *Reader) Format() string {
+	return r.format
+}
+
+// Line is the line the last row read starts on.
+func (r *Reader) Line() int {
+	return r.line
+}
+
+// detect peeks past leading blank space, keeping line numbers intact.
+func detect(buf *bufio.Reader) string {
+	for n := 1; ; n++ {
+		data, _ := buf.Peek(n)
+		if len(data) < n {
+			return FormatCSV
+		}
+		switch data[n-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '{':
+			return FormatNDJSON
+		default:
+			return FormatCSV
+		}
+	}
+}
+
+func (r *Reader) csv(buf *bufio.Reader) (func() (entities.Song, error), error) {
+	reader := csv.NewReader(buf)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("header row is missing")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimPrefix(strings.TrimSpace(name), "\uFEFF")] = i
+	}
+	for _, column := range []string{"group", "song"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("column %s is missing", column)
+		}
+	}
+
+	return func() (entities.Song, error) {
+		record, err := reader.Read()
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			r.line = parseErr.StartLine
+			return entities.Song{}, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+		}
+		if err != nil {
+			return entities.Song{}, err
+		}
+		r.line, _ = reader.FieldPos(0)
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+		song := entities.Song{
+			GroupName: value("group"),
+			Song:      value("song"),
+			Text:      value("text"),
+			Link:      value("link"),
+		}
+		if song.ReleaseDate, err = entities.ParseDate(value("releaseDate")); err != nil {
+			return song, &RowError{Line: r.line, Err: err}
+		}
+		if song.Album, err = album(value); err != nil {
+			return song, &RowError{Line: r.line, Err: err}
+		}
+		return song, nil
+	}, nil
+}
+
+// album reads the optional album, albumReleaseDate, albumCover, disc and
+// track columns of a CSV row.
+func album(value func(column string) string) (*entities.SongAlbum, error) {
+	title := value("album")
+	if title == "" {
+		return nil, nil
+	}
+	album := &entities.SongAlbum{
+		Title:     title,
+		CoverLink: value("albumCover"),
+	}
+	var err error
+	if album.ReleaseDate, err = entities.ParseDate(value("albumReleaseDate")); err != nil {
+		return nil, err
+	}
+	if album.DiscNumber, err = number(value("disc")); err != nil {
+		return nil, fmt.Errorf("incorrect disc: %w", err)
+	}
+	if album.TrackNumber, err = number(value("track")); err != nil {
+		return nil, fmt.Errorf("incorrect track: %w", err)
+	}
+	return album, nil
+}
+
+func number(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(value))
+}
+
+// ndjsonSong is the part of the song json a row may set.
+type ndjsonSong struct {
+	Group       string              `json:"group"`
+	Song        string              `json:"song"`
+	ReleaseDate entities.Date       `json:"releaseDate"`
+	Text        string              `json:"text"`
+	Link        string              `json:"link"`
+	Album       *entities.SongAlbum `json:"album"`
+}
+
+func (r *Reader) ndjson(buf *bufio.Reader) func() (entities.Song, error) {
+	next := 1
+	return func() (entities.Song, error) {
+		for {
+			// bufio.Scanner would cap the line length, and lyrics are long
+			data, err := buf.ReadBytes('\n')
+			if len(data) == 0 && err != nil {
+				return entities.Song{}, err
+			}
+			if err != nil && err != io.EOF {
+				return entities.Song{}, err
+			}
+			r.line = next
+			next++
+			data = bytes.TrimSpace(data)
+			if len(data) == 0 {
+				continue
+			}
+			var row ndjsonSong
+			if err := json.Unmarshal(data, &row); err != nil {
+				return entities.Song{}, &RowError{Line: r.line, Err: err}
+			}
+			if row.Album != nil && row.Album.Title == "" {
+				row.Album = nil
+			}
+			return entities.Song{
+				GroupName:   row.Group,
+				Song:        row.Song,
+				ReleaseDate: row.ReleaseDate,
+				Text:        row.Text,
+				Link:        row.Link,
+				Album:       row.Album,
+			}, nil
+		}
+	}
+}
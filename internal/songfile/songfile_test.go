@@ -0,0 +1,173 @@
+package songfile
+
+import (
+	"effectiveMobile/internal/entities"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// row is a song read or the line of a row that could not be.
+type row struct {
+	Song entities.Song
+	Line int
+}
+
+func readAll(t *testing.T, r *Reader) []row {
+	t.Helper()
+	var rows []row
+	for {
+		song, err := r.Read()
+		if err == io.EOF {
+			return rows
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			if rowErr.Line != r.Line() {
+				t.Errorf("RowError line %d, Line() %d", rowErr.Line, r.Line())
+			}
+			rows = append(rows, row{Line: rowErr.Line})
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Read error = %v", err)
+		}
+		rows = append(rows, row{Song: song})
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []row
+	}{
+		{"header only", "group,song\n", nil},
+		{
+			name:  "columns in any order, unknown ones skipped",
+			input: "\uFEFFid,song, group ,status,releaseDate\n1,Uprising,Muse,enriched,16.07.2009\n2,Hysteria,Muse,,2003\n",
+			want: []row{
+				{Song: entities.Song{GroupName: "Muse", Song: "Uprising", ReleaseDate: entities.NewDate(2009, time.July, 16)}},
+				{Song: entities.Song{GroupName: "Muse", Song: "Hysteria", ReleaseDate: entities.NewDate(2003, time.January, 1)}},
+			},
+		},
+		{
+			name:  "quoted multi-line text keeps line numbers",
+			input: "group,song,text\nMuse,Uprising,\"Paranoia is in bloom,\nthe PR transmissions\"\n,Nameless,\nMuse,  ,\n",
+			want: []row{
+				{Song: entities.Song{GroupName: "Muse", Song: "Uprising", Text: "Paranoia is in bloom,\nthe PR transmissions"}},
+				{Line: 4},
+				{Line: 5},
+			},
+		},
+		{
+			name:  "short rows and bad values",
+			input: "group,song,link,releaseDate\nMuse,Uprising\nMuse,Hysteria,,yesterday\nMuse,\"Starlight\n",
+			want: []row{
+				{Song: entities.Song{GroupName: "Muse", Song: "Uprising"}},
+				{Line: 3},
+				{Line: 4},
+			},
+		},
+		{
+			name:  "album columns",
+			input: "group,song,album,albumReleaseDate,albumCover,disc,track\nMuse,Uprising,The Resistance,2009-09-14,http://cover, 1 ,1\nMuse,Hysteria,,,,x,y\nMuse,Starlight,Black Holes,,,one,\n",
+			want: []row{
+				{Song: entities.Song{GroupName: "Muse", Song: "Uprising", Album: &entities.SongAlbum{
+					Title: "The Resistance", ReleaseDate: entities.NewDate(2009, time.September, 14), CoverLink: "http://cover", DiscNumber: 1, TrackNumber: 1,
+				}}},
+				// no album title, so the album columns are not looked at
+				{Song: entities.Song{GroupName: "Muse", Song: "Hysteria"}},
+				{Line: 4},
+			},
+		},
+	}
+	for _, test := range tests {
+		reader, err := NewReader(strings.NewReader(test.input), FormatCSV)
+		if err != nil {
+			t.Errorf("%s: NewReader error = %v", test.name, err)
+			continue
+		}
+		if got := readAll(t, reader); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: read %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReadCSVHeader(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"", "header row is missing"},
+		{"group,text\nMuse,a\n", "column song is missing"},
+		{"song,text\nUprising,a\n", "column group is missing"},
+		{"Group,Song\nMuse,Uprising\n", "column group is missing"},
+		{"\"group,song\n", "extraneous or missing \" in quoted-field"},
+	}
+	for _, test := range tests {
+		reader, err := NewReader(strings.NewReader(test.input), "")
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("NewReader(%q) = %v, %v, want error %q", test.input, reader, err, test.err)
+		}
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	input := "\n  {\"group\": \" Muse \", \"song\": \"Uprising\", \"releaseDate\": \"2009\", \"album\": {\"title\": \"The Resistance\", \"trackNumber\": 1}}\r\n" +
+		"{\"group\": \"Muse\", \"song\": \"Hysteria\", \"album\": {\"title\": \"\"}, \"status\": \"ignored\"}\n" +
+		"\n" +
+		"{\"group\": \"Muse\"}\n" +
+		"not json\n" +
+		"{\"group\": \"Muse\", \"song\": \"Starlight\", \"releaseDate\": \"someday\"}\n" +
+		"{\"group\": \"Muse\", \"song\": \"" + strings.Repeat("la ", 100000) + "\"}"
+	reader, err := NewReader(strings.NewReader(input), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reader.Format() != FormatNDJSON {
+		t.Errorf("format = %s, want %s", reader.Format(), FormatNDJSON)
+	}
+	want := []row{
+		{Song: entities.Song{GroupName: "Muse", Song: "Uprising", ReleaseDate: entities.NewDate(2009, time.January, 1),
+			Album: &entities.SongAlbum{Title: "The Resistance", TrackNumber: 1}}},
+		{Song: entities.Song{GroupName: "Muse", Song: "Hysteria"}},
+		{Line: 5},
+		{Line: 6},
+		{Line: 7},
+		{Song: entities.Song{GroupName: "Muse", Song: strings.TrimSpace(strings.Repeat("la ", 100000))}},
+	}
+	if got := readAll(t, reader); !reflect.DeepEqual(got, want) {
+		t.Errorf("read %+v, want %+v", got, want)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"group,song\n", FormatCSV},
+		{" \r\n\t{\"group\": \"Muse\", \"song\": \"Uprising\"}\n", FormatNDJSON},
+		{"[{\"group\": \"Muse\"}]", FormatCSV},
+	}
+	for _, test := range tests {
+		reader, err := NewReader(strings.NewReader(test.input), "")
+		if err != nil {
+			// a json array is not NDJSON, and as CSV it has no song column
+			if test.want != FormatCSV {
+				t.Errorf("NewReader(%q) error = %v", test.input, err)
+			}
+			continue
+		}
+		if reader.Format() != test.want {
+			t.Errorf("NewReader(%q) format = %s, want %s", test.input, reader.Format(), test.want)
+		}
+	}
+	if _, err := NewReader(strings.NewReader("group,song"), "xml"); err == nil {
+		t.Errorf("NewReader with format xml succeeded")
+	}
+}
@@ -0,0 +1,127 @@
+package songfile
+
+import (
+	"bufio"
+	"effectiveMobile/internal/entities"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// FormatJSON is a json array of songs. It is written only: a file to read
+// back is better off as NDJSON.
+const FormatJSON = "json"
+
+// csvColumns is the header of written CSV. NewReader reads the columns it
+// knows and skips id, artistId and status.
+var csvColumns = []string{"id", "group", "song", "artistId", "releaseDate", "text", "link", "status"}
+
+// IsWriteFormat tells whether songs can be written in format.
+func IsWriteFormat(format string) bool {
+	return IsFormat(format) || format == FormatJSON
+}
+
+// ContentType returns the media type of a format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// Writer writes songs one by one. Close finishes the file, it does not
+// close the underlying writer.
+type Writer struct {
+	format string
+	// buf writes json; csv has a buffer of its own
+	buf   *bufio.Writer
+	csv   *csv.Writer
+	count int
+}
+
+func NewWriter(w io.Writer, format string) (*Writer, error) {
+	if !IsWriteFormat(format) {
+		return nil, fmt.Errorf("unsupported song file format %q", format)
+	}
+	writer := &Writer{
+		format: format,
+		buf:    bufio.NewWriter(w),
+	}
+	if format == FormatCSV {
+		writer.csv = csv.NewWriter(w)
+	}
+	return writer, nil
+}
+
+func (w *Writer) Write(song entities.Song) error {
+	w.count++
+	switch w.format {
+	case FormatCSV:
+		if w.count == 1 {
+			if err := w.csv.Write(csvColumns); err != nil {
+				return err
+			}
+		}
+		artistId := ""
+		if song.ArtistID != nil {
+			artistId = strconv.FormatUint(uint64(*song.ArtistID), 10)
+		}
+		return w.csv.Write([]string{
+			strconv.FormatUint(uint64(song.ID), 10), song.GroupName, song.Song, artistId,
+			song.ReleaseDate.String(), song.Text, song.Link, song.Status,
+		})
+	default:
+		data, err := json.Marshal(song)
+		if err != nil {
+			return err
+		}
+		if w.format == FormatJSON {
+			separator := ",\n"
+			if w.count == 1 {
+				separator = "[\n"
+			}
+			if _, err := w.buf.WriteString(separator); err != nil {
+				return err
+			}
+		}
+		if _, err := w.buf.Write(data); err != nil {
+			return err
+		}
+		if w.format == FormatNDJSON {
+			return w.buf.WriteByte('\n')
+		}
+		return nil
+	}
+}
+
+func (w *Writer) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return w.buf.Flush()
+}
+
+// Close writes what the format needs after the last song, such as the CSV
+// header of a file without songs or the end of the json array, and flushes.
+func (w *Writer) Close() error {
+	switch {
+	case w.format == FormatCSV && w.count == 0:
+		if err := w.csv.Write(csvColumns); err != nil {
+			return err
+		}
+	case w.format == FormatJSON && w.count == 0:
+		w.buf.WriteString("[]\n")
+	case w.format == FormatJSON:
+		w.buf.WriteString("\n]\n")
+	}
+	return w.flush()
+}
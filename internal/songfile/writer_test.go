@@ -0,0 +1,104 @@
+package songfile
+
+import (
+	"bytes"
+	"effectiveMobile/internal/entities"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testSongs = []entities.Song{
+	{ID: 1, GroupName: "Muse", Song: "Uprising", ReleaseDate: entities.NewDate(2009, time.September, 7),
+		Text: "Paranoia is in bloom,\n\"the PR\" transmissions", Link: "http://example.com", Status: entities.SongStatusEnriched,
+		Album: &entities.SongAlbum{Title: "The Resistance", DiscNumber: 1, TrackNumber: 1}},
+	{ID: 2, GroupName: "Кино", Song: "Кукушка", Status: entities.SongStatusPendingEnrichment},
+}
+
+func write(t *testing.T, format string, songs []entities.Song) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, song := range songs {
+		if err := writer.Write(song); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriteEmpty(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatCSV, "id,group,song,artistId,releaseDate,text,link,status\n"},
+		{FormatNDJSON, ""},
+		{FormatJSON, "[]\n"},
+	}
+	for _, test := range tests {
+		if got := string(write(t, test.format, nil)); got != test.want {
+			t.Errorf("%s: wrote %q, want %q", test.format, got, test.want)
+		}
+	}
+	var songs []entities.Song
+	if err := json.Unmarshal(write(t, FormatJSON, nil), &songs); err != nil || songs == nil || len(songs) != 0 {
+		t.Errorf("empty json array is read as %v, %v", songs, err)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var songs []entities.Song
+	data := write(t, FormatJSON, testSongs)
+	if err := json.Unmarshal(data, &songs); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error = %v", data, err)
+	}
+	if !reflect.DeepEqual(songs, testSongs) {
+		t.Errorf("read %+v, want %+v", songs, testSongs)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatNDJSON} {
+		data := write(t, format, testSongs)
+		reader, err := NewReader(bytes.NewReader(data), "")
+		if err != nil {
+			t.Fatalf("%s: NewReader(%q) error = %v", format, data, err)
+		}
+		if reader.Format() != format {
+			t.Errorf("%s: detected %s", format, reader.Format())
+		}
+		got := readAll(t, reader)
+		var want []row
+		for _, song := range testSongs {
+			// only what a song file sets is read back
+			read := entities.Song{
+				GroupName:   song.GroupName,
+				Song:        song.Song,
+				ReleaseDate: song.ReleaseDate,
+				Text:        song.Text,
+				Link:        song.Link,
+			}
+			if format == FormatNDJSON {
+				read.Album = song.Album
+			}
+			want = append(want, row{Song: read})
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: read %+v, want %+v", format, got, want)
+		}
+	}
+}
+
+func TestNewWriterUnsupported(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "xml"); err == nil {
+		t.Errorf("NewWriter with format xml succeeded")
+	}
+}
@@ -3,7 +3,10 @@ package store
 import (
 	"context"
 	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/errors"
 	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/migrations"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
@@ -12,25 +15,62 @@ import (
 
 type Store struct {
 	Songs
+	Jobs
+	Artists
+	Albums
+	Playlists
+	Imports
 }
 
 func NewStore(db *gorm.DB) Store {
 	return Store{
-		Songs: NewStoreSongs(db),
+		Songs:     NewStoreSongs(db),
+		Jobs:      NewStoreJobs(db),
+		Artists:   NewStoreArtists(db),
+		Albums:    NewStoreAlbums(db),
+		Playlists: NewStorePlaylists(db),
+		Imports:   NewStoreImports(db),
 	}
 }
 
-func InitDB(ctx context.Context, connString string) (*gorm.DB, error) {
+func InitDB(ctx context.Context, connString string, migrateOnStart bool) (*gorm.DB, error) {
 	log := logger.LoggerFromContext(ctx)
-	log.Debugw("connecting to database")
-	db, err := gorm.Open(postgres.Open(connString), &gorm.Config{})
+	db, err := OpenDB(ctx, connString)
 	if err != nil {
-		log.Errorw("error with opening database conn", zap.Error(err))
 		return nil, err
 	}
 
-	if err := db.AutoMigrate(&entities.Song{}); err != nil {
-		log.Errorw("error with migrating database scheme", zap.Error(err))
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		log.Errorw("error with loading migrations", zap.Error(err))
+		return nil, err
+	}
+
+	if migrateOnStart {
+		if err := migrator.Up(ctx); err != nil {
+			log.Errorw("error with migrating database scheme", zap.Error(err))
+			return nil, err
+		}
+	} else {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			log.Errorw("error with checking database scheme", zap.Error(err))
+			return nil, err
+		}
+		if len(pending) > 0 {
+			log.Errorw("database scheme is behind", "pending", len(pending))
+			return nil, errors.ErrSchemaOutdated
+		}
+	}
+
+	if err := backfillSearchKeys(ctx, db); err != nil {
+		return nil, err
+	}
+	if err := backfillSections(ctx, db); err != nil {
+		return nil, err
+	}
+	if err := ensureSongNameIndex(ctx, db); err != nil {
+		log.Errorw("error with creating song name index", zap.Error(err))
 		return nil, err
 	}
 
@@ -39,6 +79,25 @@ func InitDB(ctx context.Context, connString string) (*gorm.DB, error) {
 	return db, nil
 }
 
+func OpenDB(ctx context.Context, connString string) (*gorm.DB, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Debugw("connecting to database")
+	db, err := gorm.Open(postgres.Open(connString), &gorm.Config{})
+	if err != nil {
+		log.Errorw("error with opening database conn", zap.Error(err))
+		return nil, err
+	}
+	return db, nil
+}
+
+func NewMigrator(db *gorm.DB) (*migrations.Migrator, error) {
+	dbSQL, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrations.NewMigrator(dbSQL)
+}
+
 func ShutdownDb(ctx context.Context, db *gorm.DB) error {
 	log := logger.LoggerFromContext(ctx)
 	log.Debugw("closing database conn")
@@ -53,8 +112,66 @@ func ShutdownDb(ctx context.Context, db *gorm.DB) error {
 
 type Songs interface {
 	InsertSong(ctx context.Context, req entities.Song) (int, error)
-	GetSongs(ctx context.Context, filters entities.Song, limit, offset int) ([]entities.Song, error)
+	GetSongs(ctx context.Context, filters entities.SongFilters, sort []entities.SortKey, limit, offset int) ([]entities.Song, error)
+	GetSongsPage(ctx context.Context, filters entities.SongFilters, sort []entities.SortKey, cursor *entities.SongCursor, limit int) ([]entities.Song, bool, error)
+	CountSongs(ctx context.Context, filters entities.SongFilters, estimate bool) (int64, bool, error)
+	StreamSongs(ctx context.Context, filters entities.SongFilters, sort []entities.SortKey, batch int, fn func([]entities.Song) error) error
 	DeleteSong(ctx context.Context, songId int) error
 	GetTextSong(ctx context.Context, songId int) (string, error)
 	UpdateSong(ctx context.Context, songId int, song entities.SongUpdate) error
+	GetSong(ctx context.Context, songId int) (entities.Song, error)
+	FindSong(ctx context.Context, group, song string) (entities.Song, error)
+	MergeEnrichment(ctx context.Context, songId int, details entities.Song) (entities.Song, error)
+	GetStaleSongs(ctx context.Context, olderThan time.Time, limit int) ([]entities.Song, error)
+	GetSections(ctx context.Context, songId int, kind string, number int) ([]entities.LyricsSection, error)
+	ImportSyncedLyrics(ctx context.Context, songId int, text string, offsetMs int, timings []entities.LyricsTiming) error
+	GetSyncedLyrics(ctx context.Context, songId int) (entities.Song, []entities.LyricsTiming, error)
+	SearchLyrics(ctx context.Context, query string, languages []string, headlineConfig string, limit, offset int) ([]entities.SearchResult, int64, error)
+	FuzzySearch(ctx context.Context, query string, fields []string, threshold float64, limit, offset int) ([]entities.FuzzyResult, int64, error)
+	FindDuplicates(ctx context.Context, threshold float64, limit int) ([]entities.DuplicatePair, error)
+	GetSongsByIds(ctx context.Context, ids []uint) ([]entities.Song, error)
+	MergeSongs(ctx context.Context, keepId uint, duplicateIds []uint) (entities.Song, error)
+}
+
+type Jobs interface {
+	InsertSongWithJob(ctx context.Context, song entities.Song, maxAttempts int) (int, int, error)
+	ClaimJob(ctx context.Context, lease time.Duration) (entities.EnrichmentJob, entities.Song, error)
+	CompleteJob(ctx context.Context, job entities.EnrichmentJob, details entities.Song) error
+	RescheduleJob(ctx context.Context, job entities.EnrichmentJob, lastError string, runAt time.Time) error
+	BuryJob(ctx context.Context, job entities.EnrichmentJob, lastError string) error
+	GetJob(ctx context.Context, jobId int) (entities.EnrichmentJob, error)
+	RetryJob(ctx context.Context, jobId int) error
+}
+
+type Artists interface {
+	InsertArtist(ctx context.Context, name string) (entities.Artist, error)
+	GetArtists(ctx context.Context, name string, limit, offset int) ([]entities.Artist, int64, error)
+	GetArtist(ctx context.Context, artistId int) (entities.Artist, error)
+	RenameArtist(ctx context.Context, artistId int, name string) (entities.Artist, error)
+	DeleteArtist(ctx context.Context, artistId int) error
+}
+
+type Albums interface {
+	InsertAlbum(ctx context.Context, album entities.Album) (entities.Album, error)
+	GetAlbums(ctx context.Context, artistId uint, title string, limit, offset int) ([]entities.Album, int64, error)
+	GetAlbum(ctx context.Context, albumId int) (entities.Album, error)
+}
+
+type Playlists interface {
+	InsertPlaylist(ctx context.Context, playlist entities.Playlist) (entities.Playlist, error)
+	GetPlaylists(ctx context.Context, limit, offset int) ([]entities.Playlist, int64, error)
+	GetPlaylist(ctx context.Context, playlistId int) (entities.Playlist, error)
+	UpdatePlaylist(ctx context.Context, playlistId int, update entities.PlaylistUpdate) (entities.Playlist, error)
+	DeletePlaylist(ctx context.Context, playlistId int, version *int) error
+	AddEntry(ctx context.Context, playlistId int, songId uint, position int, version *int) (entities.Playlist, error)
+	MoveEntry(ctx context.Context, playlistId int, entryId uint, position int, version *int) (entities.Playlist, error)
+	RemoveEntry(ctx context.Context, playlistId int, entryId uint, version *int) (entities.Playlist, error)
+	ReorderPlaylist(ctx context.Context, playlistId int, entryIds []uint, version *int) (entities.Playlist, error)
+}
+
+type Imports interface {
+	InsertImport(ctx context.Context, format string) (entities.SongImport, error)
+	SaveImportRows(ctx context.Context, importId uint, rows []entities.SongImportRow) ([]entities.SongImportRow, error)
+	FinishImport(ctx context.Context, importId uint, status, message string) error
+	GetImport(ctx context.Context, importId int, status string, limit, offset int) (entities.SongImport, error)
 }
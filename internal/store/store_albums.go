@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// albumColumns selects an album with its artist name and track count; the
+// query must join artists with joinAlbumArtist.
+const albumColumns = "albums.*, artists.name AS artist, (SELECT count(*) FROM album_tracks WHERE album_tracks.album_id = albums.id) AS track_count"
+
+const joinAlbumArtist = "LEFT JOIN artists ON artists.id = albums.artist_id"
+
+type StoreAlbums struct {
+	db *gorm.DB
+}
+
+func NewStoreAlbums(db *gorm.DB) *StoreAlbums {
+	return &StoreAlbums{
+		db: db,
+	}
+}
+
+// InsertAlbum stores the album with its numbered tracks. album.Artist is
+// the artist name, found or created like the group of a song.
+func (r *StoreAlbums) InsertAlbum(ctx context.Context, album entities.Album) (entities.Album, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("inserting album")
+	var inserted entities.Album
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		artistId, _, err := ensureArtist(tx, album.Artist)
+		if err != nil {
+			return err
+		}
+		var albumId uint
+		result := tx.Raw("INSERT INTO albums (title, artist_id, release_date, cover_link) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING RETURNING id",
+			strings.TrimSpace(album.Title), artistId, album.ReleaseDate, album.CoverLink).
+			Scan(&albumId)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return projectError.ErrAlbumExists
+		}
+
+		if len(album.Tracks) > 0 {
+			songIds := make([]uint, 0, len(album.Tracks))
+			for i := range album.Tracks {
+				album.Tracks[i].AlbumID = albumId
+				songIds = append(songIds, album.Tracks[i].SongID)
+			}
+			var found int64
+			if err := tx.Model(&entities.Song{}).Where("id IN ?", songIds).Count(&found).Error; err != nil {
+				return err
+			}
+			if found != int64(len(songIds)) {
+				return projectError.ErrSongNotFound
+			}
+			if err := tx.Create(&album.Tracks).Error; err != nil {
+				return err
+			}
+		}
+
+		inserted, err = getAlbum(tx, albumId)
+		return err
+	})
+	if err != nil {
+		log.Errorw("error with inserting album", zap.Error(err))
+		return inserted, err
+	}
+	log.Infow("album is inserted", "albumId", inserted.ID)
+	return inserted, nil
+}
+
+// GetAlbums lists albums, optionally of one artist and with title
+// containing title, ordered by title, and the total number of such albums.
+func (r *StoreAlbums) GetAlbums(ctx context.Context, artistId uint, title string, limit, offset int) ([]entities.Album, int64, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting albums")
+	query := r.db.WithContext(ctx).Model(&entities.Album{})
+	if artistId != 0 {
+		query = query.Where("albums.artist_id = ?", artistId)
+	}
+	if title != "" {
+		query = query.Where("strpos(lower(albums.title), lower(?)) > 0", title)
+	}
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		log.Errorw("error with counting albums", zap.Error(err))
+		return nil, 0, err
+	}
+	var albums []entities.Album
+	err := query.Select(albumColumns).
+		Joins(joinAlbumArtist).
+		Order("lower(albums.title), albums.id").
+		Offset((offset - 1) * limit).
+		Limit(limit).
+		Find(&albums).Error
+	if err != nil {
+		log.Errorw("error with getting albums", zap.Error(err))
+		return nil, 0, err
+	}
+	log.Infow("albums are got", "count", len(albums))
+	return albums, total, nil
+}
+
+// GetAlbum returns the album with its tracks by disc and track number.
+func (r *StoreAlbums) GetAlbum(ctx context.Context, albumId int) (entities.Album, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting album")
+	album, err := getAlbum(r.db.WithContext(ctx), uint(albumId))
+	if err != nil {
+		if !errors.Is(err, projectError.ErrAlbumNotFound) {
+			log.Errorw("error with getting album", zap.Error(err))
+		}
+		return album, err
+	}
+	log.Infow("album is got", "albumId", albumId)
+	return album, nil
+}
+
+func getAlbum(db *gorm.DB, albumId uint) (entities.Album, error) {
+	var album entities.Album
+	err := db.Select(albumColumns).Joins(joinAlbumArtist).First(&album, albumId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return album, projectError.ErrAlbumNotFound
+	}
+	if err != nil {
+		return album, err
+	}
+	album.Tracks = make([]entities.AlbumTrack, 0)
+	err = db.Model(&entities.AlbumTrack{}).
+		Select("album_tracks.*, songs.group_name, songs.song").
+		Joins("JOIN songs ON songs.id = album_tracks.song_id").
+		Where("album_tracks.album_id = ?", albumId).
+		Order("album_tracks.disc_number, album_tracks.track_number").
+		Find(&album.Tracks).Error
+	return album, err
+}
+
+// linkAlbum puts the song on the album enrichment found it on, creating the
+// album if needed. Album fields already set are kept, empty ones are
+// filled. A taken track number leaves the song off the album.
+func linkAlbum(tx *gorm.DB, songId uint, artistId *uint, album *entities.SongAlbum) error {
+	if album == nil || strings.TrimSpace(album.Title) == "" {
+		return nil
+	}
+	var albumId uint
+	err := tx.Raw(`INSERT INTO albums (title, artist_id, release_date, cover_link) VALUES (?, ?, ?, ?)
+		ON CONFLICT ((coalesce(artist_id, 0)), (lower(btrim(title)))) DO UPDATE SET
+			release_date = coalesce(albums.release_date, excluded.release_date),
+			cover_link = CASE WHEN albums.cover_link = '' THEN excluded.cover_link ELSE albums.cover_link END
+		RETURNING id`,
+		strings.TrimSpace(album.Title), artistId, album.ReleaseDate, album.CoverLink).
+		Scan(&albumId).Error
+	if err != nil {
+		return err
+	}
+	disc := max(album.DiscNumber, 1)
+	return tx.Exec(`INSERT INTO album_tracks (album_id, song_id, disc_number, track_number)
+		SELECT ?, ?, ?, coalesce(nullif(?::integer, 0),
+			(SELECT coalesce(max(track_number), 0) + 1 FROM album_tracks WHERE album_id = ? AND disc_number = ?))
+		ON CONFLICT DO NOTHING`,
+		albumId, songId, disc, album.TrackNumber, albumId, disc).Error
+}
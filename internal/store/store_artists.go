@@ -0,0 +1,228 @@
+package store
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/translit"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// artistNameKey mirrors the generated artists.name_key column of migration
+// 0011 for use in queries.
+const artistNameKey = "lower(regexp_replace(btrim(?::text), '\\s+', ' ', 'g'))"
+
+// artistColumns selects an artist with its song count.
+const artistColumns = "artists.*, (SELECT count(*) FROM songs WHERE songs.artist_id = artists.id) AS song_count"
+
+type StoreArtists struct {
+	db *gorm.DB
+}
+
+func NewStoreArtists(db *gorm.DB) *StoreArtists {
+	return &StoreArtists{
+		db: db,
+	}
+}
+
+func (r *StoreArtists) InsertArtist(ctx context.Context, name string) (entities.Artist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("inserting artist")
+	var artist entities.Artist
+	result := r.db.WithContext(ctx).
+		Raw("INSERT INTO artists (name) VALUES (?) ON CONFLICT (name_key) DO NOTHING RETURNING *", artistName(name)).
+		Scan(&artist)
+	if result.Error != nil {
+		log.Errorw("error with inserting artist", zap.Error(result.Error))
+		return artist, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return artist, projectError.ErrArtistExists
+	}
+	log.Infow("artist is inserted", "artistId", artist.ID)
+	return artist, nil
+}
+
+// GetArtists lists artists whose name contains name, case-insensitively,
+// ordered by name, and the total number of such artists.
+func (r *StoreArtists) GetArtists(ctx context.Context, name string, limit, offset int) ([]entities.Artist, int64, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting artists")
+	query := r.db.WithContext(ctx).Model(&entities.Artist{})
+	if name != "" {
+		query = query.Where("strpos(name_key, "+artistNameKey+") > 0", name)
+	}
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		log.Errorw("error with counting artists", zap.Error(err))
+		return nil, 0, err
+	}
+	var artists []entities.Artist
+	err := query.Select(artistColumns).
+		Order("name_key, id").
+		Offset((offset - 1) * limit).
+		Limit(limit).
+		Find(&artists).Error
+	if err != nil {
+		log.Errorw("error with getting artists", zap.Error(err))
+		return nil, 0, err
+	}
+	log.Infow("artists are got", "count", len(artists))
+	return artists, total, nil
+}
+
+func (r *StoreArtists) GetArtist(ctx context.Context, artistId int) (entities.Artist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting artist")
+	var artist entities.Artist
+	err := r.db.WithContext(ctx).Select(artistColumns).First(&artist, artistId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return artist, projectError.ErrArtistNotFound
+	}
+	if err != nil {
+		log.Errorw("error with getting artist", zap.Error(err))
+		return artist, err
+	}
+	log.Infow("artist is got", "artistId", artistId)
+	return artist, nil
+}
+
+// RenameArtist renames the artist and refreshes the cached group name of
+// its songs in the same transaction. It fails with ErrSongExists if a song
+// would get the name of a song of another artist.
+func (r *StoreArtists) RenameArtist(ctx context.Context, artistId int, name string) (entities.Artist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started renaming artist")
+	name = artistName(name)
+	var artist entities.Artist
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&artist, artistId).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return projectError.ErrArtistNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var clashes int64
+		err = tx.Model(&entities.Artist{}).
+			Where("name_key = "+artistNameKey+" AND id <> ?", name, artistId).
+			Count(&clashes).Error
+		if err != nil {
+			return err
+		}
+		if clashes > 0 {
+			return projectError.ErrArtistExists
+		}
+		var songs []string
+		if err := tx.Model(&entities.Song{}).Where("artist_id = ?", artistId).Pluck("song", &songs).Error; err != nil {
+			return err
+		}
+		// the renamed songs take their new names as inserts and updates do
+		keys := make([]string, 0, len(songs))
+		for _, song := range songs {
+			keys = append(keys, SongNameKey(name, song))
+		}
+		if err := lockSongNames(tx, keys); err != nil {
+			return err
+		}
+		if err := tx.Model(&artist).Update("name", name).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&entities.Song{}).Where("artist_id = ?", artistId).Updates(map[string]interface{}{
+			"group_name": name,
+			"group_key":  translit.Key(name),
+		}).Error
+		if err != nil {
+			// the artist has a song another artist has under the new name
+			return songNameConflict(err)
+		}
+		return tx.Select(artistColumns).First(&artist, artistId).Error
+	})
+	if err != nil {
+		log.Errorw("error with renaming artist", zap.Error(err))
+		return artist, err
+	}
+	log.Infow("artist is renamed", "artistId", artistId)
+	return artist, nil
+}
+
+// DeleteArtist deletes an artist without songs and albums.
+func (r *StoreArtists) DeleteArtist(ctx context.Context, artistId int) error {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started deleting artist")
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var artist entities.Artist
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&artist, artistId).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return projectError.ErrArtistNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var songs int64
+		if err := tx.Model(&entities.Song{}).Where("artist_id = ?", artistId).Count(&songs).Error; err != nil {
+			return err
+		}
+		if songs > 0 {
+			return projectError.ErrArtistHasSongs
+		}
+		var albums int64
+		if err := tx.Model(&entities.Album{}).Where("artist_id = ?", artistId).Count(&albums).Error; err != nil {
+			return err
+		}
+		if albums > 0 {
+			return projectError.ErrArtistHasAlbums
+		}
+		return tx.Delete(&artist).Error
+	})
+	if err != nil {
+		log.Errorw("error with deleting artist", zap.Error(err))
+		return err
+	}
+	log.Infow("artist is deleted", "artistId", artistId)
+	return nil
+}
+
+// artistName collapses whitespace the way artists.name_key does.
+func artistName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// linkArtist points song at the artist named by its group name, creating
+// the artist if needed, and stores the artist's spelling of the name. It
+// also fills the search keys, so it must run right before the insert.
+func linkArtist(tx *gorm.DB, song *entities.Song) error {
+	artistId, name, err := ensureArtist(tx, song.GroupName)
+	if err != nil {
+		return err
+	}
+	if artistId != nil {
+		song.ArtistID = artistId
+		song.GroupName = name
+	}
+	setSearchKeys(song)
+	return nil
+}
+
+// ensureArtist returns the id and name of the artist named name, creating
+// it if needed. An empty name is no artist.
+func ensureArtist(tx *gorm.DB, name string) (*uint, string, error) {
+	name = artistName(name)
+	if name == "" {
+		return nil, "", nil
+	}
+	var artist entities.Artist
+	// the no-op update makes RETURNING yield the existing row on conflict
+	err := tx.Raw("INSERT INTO artists (name) VALUES (?) ON CONFLICT (name_key) DO UPDATE SET name = artists.name RETURNING id, name", name).
+		Scan(&artist).Error
+	if err != nil {
+		return nil, "", err
+	}
+	return &artist.ID, artist.Name, nil
+}
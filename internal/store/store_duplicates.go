@@ -0,0 +1,202 @@
+package store
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"strconv"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// duplicateTextLength is how much of the lyrics FindDuplicates compares:
+// trigram similarity of whole lyrics costs a lot and says little more.
+const duplicateTextLength = 2000
+
+// FindDuplicates returns pairs of songs whose group and song keys are both
+// trigram-similar at threshold at least, or which share a name before the
+// unique index forbids that, best first. A pair of songs that
+// both have lyrics scores the mean of its name and lyrics similarity, any
+// other pair its name similarity, and pairs scoring below threshold are left
+// out.
+func (r *StoreSongs) FindDuplicates(ctx context.Context, threshold float64, limit int) ([]entities.DuplicatePair, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started finding duplicate songs")
+	length := strconv.Itoa(duplicateTextLength)
+	var pairs []entities.DuplicatePair
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// % compares against this setting, which lets it use the trigram indexes
+		err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)",
+			strconv.FormatFloat(threshold, 'f', -1, 64)).Error
+		if err != nil {
+			return err
+		}
+		return tx.Table("(?) AS pairs", tx.Table("songs AS a").
+			Joins("JOIN songs AS b ON (b.song_key % a.song_key AND b.group_key % a.group_key OR b.name_key = a.name_key) AND b.id > a.id").
+			Select("a.id AS song_id, b.id AS other_id, "+
+				"CASE WHEN a.name_key = b.name_key THEN 1 "+
+				"ELSE (similarity(a.group_key, b.group_key) + similarity(a.song_key, b.song_key)) / 2 END AS name_score, "+
+				"CASE WHEN a.text <> '' AND b.text <> '' "+
+				"THEN similarity(left(a.text, "+length+"), left(b.text, "+length+")) END AS text_score")).
+			Select("*, (name_score + coalesce(text_score, name_score)) / 2 AS score").
+			Where("(name_score + coalesce(text_score, name_score)) / 2 >= ?", threshold).
+			Order("score DESC, song_id, other_id").
+			Limit(limit).
+			Scan(&pairs).Error
+	})
+	if err != nil {
+		log.Errorw("error with finding duplicate songs", zap.Error(err))
+		return nil, err
+	}
+	log.Infow("duplicate songs are found", "pairs", len(pairs))
+	return pairs, nil
+}
+
+// GetSongsByIds returns the songs with the ids there are, by id.
+func (r *StoreSongs) GetSongsByIds(ctx context.Context, ids []uint) ([]entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting songs by ids")
+	var songs []entities.Song
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("id").Find(&songs).Error; err != nil {
+		log.Errorw("error with getting songs by ids", zap.Error(err))
+		return nil, err
+	}
+	log.Infow("songs are got", "count", len(songs))
+	return songs, nil
+}
+
+// MergeSongs merges the songs duplicateIds into the song keepId and returns
+// it; see mergeSongs.
+func (r *StoreSongs) MergeSongs(ctx context.Context, keepId uint, duplicateIds []uint) (entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started merging songs")
+	var song entities.Song
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := mergeSongs(tx, keepId, duplicateIds); err != nil {
+			return err
+		}
+		return tx.First(&song, keepId).Error
+	})
+	if err != nil {
+		log.Errorw("error with merging songs", zap.Error(err))
+		return song, err
+	}
+	log.Infow("songs are merged", "songId", keepId, "merged", duplicateIds)
+	// the merge may have been the last one the unique index waited for
+	if err := ensureSongNameIndex(ctx, r.db); err != nil {
+		log.Warnw("error with creating song name index", zap.Error(err))
+	}
+	return song, nil
+}
+
+// mergeSongs hands the playlist entries, album tracks and import rows of the
+// duplicates over to the song keepId, keeping one track per album, and
+// deletes the duplicates with their lyrics and enrichment jobs. The kept song
+// itself is left as it is. Like removeSongFromPlaylists, it locks the songs
+// first, then the playlists it changes, in id order.
+func mergeSongs(tx *gorm.DB, keepId uint, duplicateIds []uint) error {
+	var locked []uint
+	err := tx.Model(&entities.Song{}).
+		Where("id = ? OR id IN ?", keepId, duplicateIds).
+		Order("id").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Pluck("id", &locked).Error
+	if err != nil {
+		return err
+	}
+	if len(locked) != len(duplicateIds)+1 {
+		return projectError.ErrSongNotFound
+	}
+
+	var playlistIds []uint
+	err = tx.Model(&entities.Playlist{}).
+		Where("id IN (SELECT playlist_id FROM playlist_entries WHERE song_id IN ?)", duplicateIds).
+		Order("id").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Pluck("id", &playlistIds).Error
+	if err != nil {
+		return err
+	}
+	if len(playlistIds) > 0 {
+		err := tx.Model(&entities.PlaylistEntry{}).Where("song_id IN ?", duplicateIds).Update("song_id", keepId).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entities.Playlist{}).Where("id IN ?", playlistIds).Updates(map[string]interface{}{
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": gorm.Expr("now()"),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	// the track of the kept song, or of the lowest duplicate, stays
+	err = tx.Exec(`DELETE FROM album_tracks
+		WHERE song_id IN ? AND EXISTS (
+			SELECT 1 FROM album_tracks AS other
+			WHERE other.album_id = album_tracks.album_id
+				AND (other.song_id = ? OR other.song_id IN ? AND other.song_id < album_tracks.song_id)
+		)`, duplicateIds, keepId, duplicateIds).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&entities.AlbumTrack{}).Where("song_id IN ?", duplicateIds).Update("song_id", keepId).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&entities.SongImportRow{}).Where("song_id IN ?", duplicateIds).Update("song_id", keepId).Error
+	if err != nil {
+		return err
+	}
+	return tx.Delete(&entities.Song{}, duplicateIds).Error
+}
+
+// songNameIndex is the unique index on songs.name_key. Migration 0015 does
+// not create it, since songs that share a name would have to be deleted
+// without anyone choosing which to keep; ensureSongNameIndex creates it once
+// they are merged.
+const songNameIndex = "songs_name_key_unique_idx"
+
+// songNameCollisionsShown caps the song ids logged about shared names.
+const songNameCollisionsShown = 20
+
+func hasSongNameIndex(ctx context.Context, db *gorm.DB) (bool, error) {
+	var exists bool
+	err := db.WithContext(ctx).Raw("SELECT to_regclass(?) IS NOT NULL", songNameIndex).Scan(&exists).Error
+	return exists, err
+}
+
+// ensureSongNameIndex creates the unique index on song names unless songs
+// still share names, which it reports instead.
+func ensureSongNameIndex(ctx context.Context, db *gorm.DB) error {
+	log := logger.LoggerFromContext(ctx)
+	exists, err := hasSongNameIndex(ctx, db)
+	if err != nil || exists {
+		return err
+	}
+	var shared []string
+	err = db.WithContext(ctx).
+		Raw(`SELECT string_agg(id::text, ', ' ORDER BY id) FROM songs
+			WHERE name_key IS NOT NULL
+			GROUP BY name_key
+			HAVING count(*) > 1
+			ORDER BY min(id)`).
+		Scan(&shared).Error
+	if err != nil {
+		return err
+	}
+	if len(shared) > 0 {
+		log.Warnw("songs share names, so they are not unique yet: merge them, see GET /api/songs/duplicates",
+			"names", len(shared), "songs", shared[:min(len(shared), songNameCollisionsShown)])
+		return nil
+	}
+	if err := db.WithContext(ctx).Exec("CREATE UNIQUE INDEX IF NOT EXISTS " + songNameIndex + " ON songs (name_key)").Error; err != nil {
+		return err
+	}
+	log.Infow("song names are unique now")
+	return nil
+}
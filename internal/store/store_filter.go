@@ -0,0 +1,29 @@
+package store
+
+import (
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/filter"
+	stdErrors "errors"
+)
+
+// SongFilterFields is the allow-list of fields of the GetSongs filter
+// expression, by json name. Text columns are coalesced so that ne and not
+// also match songs where the column is NULL.
+var SongFilterFields = map[string]filter.Field{
+	"group":       {Column: "coalesce(group_name, '')", Kind: filter.KindText},
+	"song":        {Column: "coalesce(song, '')", Kind: filter.KindText},
+	"text":        {Column: "coalesce(text, '')", Kind: filter.KindText},
+	"link":        {Column: "coalesce(link, '')", Kind: filter.KindText},
+	"status":      {Column: "coalesce(status, '')", Kind: filter.KindText},
+	"releaseDate": {Column: "release_date", Kind: filter.KindDate, Value: filterDate},
+	"year":        {Column: "extract(year FROM release_date)", Kind: filter.KindNumber},
+	"id":          {Column: "id", Kind: filter.KindNumber},
+	"artistId":    {Column: "artist_id", Kind: filter.KindNumber},
+}
+
+func filterDate(literal string) (interface{}, error) {
+	if literal == "" {
+		return nil, stdErrors.New("empty date, use is empty")
+	}
+	return entities.ParseDate(literal)
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type StoreImports struct {
+	db *gorm.DB
+}
+
+func NewStoreImports(db *gorm.DB) *StoreImports {
+	return &StoreImports{
+		db: db,
+	}
+}
+
+// InsertImport records a running import of a file in format.
+func (r *StoreImports) InsertImport(ctx context.Context, format string) (entities.SongImport, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("inserting song import")
+	songImport := entities.SongImport{
+		Status: entities.SongImportRunning,
+		Format: format,
+	}
+	if err := r.db.WithContext(ctx).Create(&songImport).Error; err != nil {
+		log.Errorw("error with inserting song import", zap.Error(err))
+		return songImport, err
+	}
+	log.Infow("song import is inserted", "importId", songImport.ID)
+	return songImport, nil
+}
+
+// SaveImportRows records a batch of rows and adds them to the counters of
+// the import, in one transaction. Rows carrying Details are inserted as
+// songs, unless a song of the same name exists by then, which makes them
+// duplicates; the statuses and song ids of such rows are set here. Imports
+// running at once take an advisory lock per song name first, so they never
+// deadlock inserting the same songs in different orders.
+func (r *StoreImports) SaveImportRows(ctx context.Context, importId uint, rows []entities.SongImportRow) ([]entities.SongImportRow, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Infow("saving song import rows", "importId", importId, "count", len(rows))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		keys := make([]string, 0, len(rows))
+		for _, row := range rows {
+			if row.Details != nil {
+				keys = append(keys, SongNameKey(row.GroupName, row.Song))
+			}
+		}
+		if err := lockSongNames(tx, keys); err != nil {
+			return err
+		}
+
+		counts := make(map[string]int)
+		for i := range rows {
+			row := &rows[i]
+			row.ImportID = importId
+			if row.Details != nil {
+				song := *row.Details
+				var exists *projectError.SongExistsError
+				err := insertSong(tx, &song)
+				switch {
+				case err == nil:
+					row.SongID = &song.ID
+					row.Status = entities.RowInserted
+				case errors.As(err, &exists):
+					row.SongID = &exists.ID
+					row.Status = entities.RowDuplicate
+				default:
+					return err
+				}
+			}
+			counts[row.Status]++
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entities.SongImport{}).Where("id = ?", importId).Updates(map[string]interface{}{
+			"total":             gorm.Expr("total + ?", len(rows)),
+			"inserted":          gorm.Expr("inserted + ?", counts[entities.RowInserted]),
+			"duplicates":        gorm.Expr("duplicates + ?", counts[entities.RowDuplicate]),
+			"enrichment_failed": gorm.Expr("enrichment_failed + ?", counts[entities.RowEnrichmentFailed]),
+			"invalid":           gorm.Expr("invalid + ?", counts[entities.RowInvalid]),
+		}).Error
+	})
+	if err != nil {
+		log.Errorw("error with saving song import rows", zap.Error(err))
+		return nil, err
+	}
+	log.Infow("song import rows are saved", "importId", importId)
+	return rows, nil
+}
+
+// FinishImport sets the final status of the import; message is the error
+// a failed import stopped with.
+func (r *StoreImports) FinishImport(ctx context.Context, importId uint, status, message string) error {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("finishing song import")
+	err := r.db.WithContext(ctx).Model(&entities.SongImport{}).Where("id = ?", importId).Updates(map[string]interface{}{
+		"status":      status,
+		"error":       message,
+		"finished_at": time.Now(),
+	}).Error
+	if err != nil {
+		log.Errorw("error with finishing song import", zap.Error(err))
+		return err
+	}
+	log.Infow("song import is finished", "importId", importId, "status", status)
+	return nil
+}
+
+// GetImport returns the import with a page of its rows in file order,
+// only those of status unless it is empty.
+func (r *StoreImports) GetImport(ctx context.Context, importId int, status string, limit, offset int) (entities.SongImport, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting song import")
+	var songImport entities.SongImport
+	err := r.db.WithContext(ctx).First(&songImport, importId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return songImport, projectError.ErrImportNotFound
+	}
+	if err != nil {
+		log.Errorw("error with getting song import", zap.Error(err))
+		return songImport, err
+	}
+	query := r.db.WithContext(ctx).Where("import_id = ?", importId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err = query.Order("line").Offset((offset - 1) * limit).Limit(limit).Find(&songImport.Rows).Error
+	if err != nil {
+		log.Errorw("error with getting song import rows", zap.Error(err))
+		return songImport, err
+	}
+	log.Infow("song import is got", "importId", importId, "rows", len(songImport.Rows))
+	return songImport, nil
+}
@@ -0,0 +1,243 @@
+package store
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StoreJobs struct {
+	db *gorm.DB
+}
+
+func NewStoreJobs(db *gorm.DB) *StoreJobs {
+	return &StoreJobs{
+		db: db,
+	}
+}
+
+func (r *StoreJobs) InsertSongWithJob(ctx context.Context, song entities.Song, maxAttempts int) (int, int, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("inserting song with enrichment job")
+	job := entities.EnrichmentJob{
+		Status:      entities.JobStatusQueued,
+		MaxAttempts: maxAttempts,
+		RunAt:       time.Now(),
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createSong(tx, &song); err != nil {
+			return err
+		}
+		job.SongID = song.ID
+		return tx.Create(&job).Error
+	})
+	if err != nil {
+		log.Errorw("error with inserting song with enrichment job", zap.Error(err))
+		return 0, 0, err
+	}
+	log.Infow("song with enrichment job is inserted", "songId", song.ID, "jobId", job.ID)
+	return int(song.ID), int(job.ID), nil
+}
+
+// ClaimJob takes the oldest due job, marks it running until the lease expires
+// and returns it with its song. Jobs whose lease expired, e.g. because the
+// worker died, are claimed again, unless that was their last attempt: those
+// are buried. ErrJobNotFound means nothing is due.
+func (r *StoreJobs) ClaimJob(ctx context.Context, lease time.Duration) (entities.EnrichmentJob, entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	var (
+		job  entities.EnrichmentJob
+		song entities.Song
+	)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for {
+			job = entities.EnrichmentJob{}
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("(status = ? AND run_at <= now()) OR (status = ? AND locked_until < now())",
+					entities.JobStatusQueued, entities.JobStatusRunning).
+				Order("run_at").
+				First(&job).Error
+			if err != nil {
+				return err
+			}
+			if job.Status != entities.JobStatusRunning || job.Attempts < job.MaxAttempts {
+				break
+			}
+			if err := buryJob(tx, job, "lease expired on the last attempt"); err != nil {
+				return err
+			}
+			log.Warnw("enrichment job is dead", "jobId", job.ID, "songId", job.SongID, "lastError", "lease expired on the last attempt")
+		}
+		lockedUntil := time.Now().Add(lease)
+		job.Status = entities.JobStatusRunning
+		job.Attempts++
+		job.LockedUntil = &lockedUntil
+		if err := tx.Model(&job).Select("status", "attempts", "locked_until", "updated_at").Updates(&job).Error; err != nil {
+			return err
+		}
+		return tx.First(&song, job.SongID).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return job, song, projectError.ErrJobNotFound
+	}
+	if err != nil {
+		// a worker stopping cancels its claim, which is no error
+		if ctx.Err() == nil {
+			log.Errorw("error with claiming enrichment job", zap.Error(err))
+		}
+		return job, song, err
+	}
+	return job, song, nil
+}
+
+// leased updates the job only while it is still running the attempt the
+// worker claimed: once the lease expired, another worker may have claimed
+// it again, or finished it. ErrJobLeaseLost means it did.
+func leased(tx *gorm.DB, job entities.EnrichmentJob, updates map[string]interface{}) error {
+	result := tx.Model(&entities.EnrichmentJob{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, entities.JobStatusRunning, job.Attempts).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return projectError.ErrJobLeaseLost
+	}
+	return nil
+}
+
+// logJobError logs an error of finishing a job, a lost lease being only a
+// warning: the job is in the hands of another worker.
+func logJobError(log *zap.SugaredLogger, msg string, job entities.EnrichmentJob, err error) {
+	if errors.Is(err, projectError.ErrJobLeaseLost) {
+		log.Warnw("enrichment job lease is lost", "jobId", job.ID, "attempt", job.Attempts)
+		return
+	}
+	log.Errorw(msg, "jobId", job.ID, zap.Error(err))
+}
+
+// CompleteJob merges the enriched song fields and closes the job.
+func (r *StoreJobs) CompleteJob(ctx context.Context, job entities.EnrichmentJob, details entities.Song) error {
+	log := logger.LoggerFromContext(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := leased(tx, job, map[string]interface{}{
+			"status":       entities.JobStatusDone,
+			"last_error":   "",
+			"locked_until": nil,
+		})
+		if err != nil {
+			return err
+		}
+		return mergeEnrichment(tx, job.SongID, details)
+	})
+	if err != nil {
+		logJobError(log, "error with completing enrichment job", job, err)
+		return err
+	}
+	log.Infow("enrichment job is done", "jobId", job.ID, "songId", job.SongID)
+	return nil
+}
+
+// RescheduleJob puts a failed job back in the queue to run at runAt.
+func (r *StoreJobs) RescheduleJob(ctx context.Context, job entities.EnrichmentJob, lastError string, runAt time.Time) error {
+	log := logger.LoggerFromContext(ctx)
+	err := leased(r.db.WithContext(ctx), job, map[string]interface{}{
+		"status":       entities.JobStatusQueued,
+		"last_error":   lastError,
+		"run_at":       runAt,
+		"locked_until": nil,
+	})
+	if err != nil {
+		logJobError(log, "error with rescheduling enrichment job", job, err)
+		return err
+	}
+	log.Infow("enrichment job is rescheduled", "jobId", job.ID, "runAt", runAt)
+	return nil
+}
+
+// BuryJob moves a job that ran out of attempts to the dead-letter state.
+func (r *StoreJobs) BuryJob(ctx context.Context, job entities.EnrichmentJob, lastError string) error {
+	log := logger.LoggerFromContext(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return buryJob(tx, job, lastError)
+	})
+	if err != nil {
+		logJobError(log, "error with burying enrichment job", job, err)
+		return err
+	}
+	log.Warnw("enrichment job is dead", "jobId", job.ID, "songId", job.SongID, "lastError", lastError)
+	return nil
+}
+
+// buryJob moves the running job to the dead-letter state and marks its song
+// failed.
+func buryJob(tx *gorm.DB, job entities.EnrichmentJob, lastError string) error {
+	err := leased(tx, job, map[string]interface{}{
+		"status":       entities.JobStatusDead,
+		"last_error":   lastError,
+		"locked_until": nil,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Model(&entities.Song{}).Where("id = ?", job.SongID).
+		Update("status", entities.SongStatusEnrichmentFailed).Error
+}
+
+func (r *StoreJobs) GetJob(ctx context.Context, jobId int) (entities.EnrichmentJob, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting enrichment job")
+	var job entities.EnrichmentJob
+	err := r.db.WithContext(ctx).First(&job, jobId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return job, projectError.ErrJobNotFound
+	}
+	if err != nil {
+		log.Errorw("error with getting enrichment job", zap.Error(err))
+		return job, err
+	}
+	log.Infow("enrichment job is got", "jobId", jobId)
+	return job, nil
+}
+
+// RetryJob requeues a dead job with a fresh attempt budget.
+func (r *StoreJobs) RetryJob(ctx context.Context, jobId int) error {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started retrying enrichment job")
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job entities.EnrichmentJob
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, jobId).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return projectError.ErrJobNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if job.Status != entities.JobStatusDead {
+			return projectError.ErrJobNotRetryable
+		}
+		err = tx.Model(&job).Updates(map[string]interface{}{
+			"status":   entities.JobStatusQueued,
+			"attempts": 0,
+			"run_at":   time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&entities.Song{}).Where("id = ?", job.SongID).
+			Update("status", entities.SongStatusPendingEnrichment).Error
+	})
+	if err != nil {
+		log.Errorw("error with retrying enrichment job", "jobId", jobId, zap.Error(err))
+		return err
+	}
+	log.Infow("enrichment job is queued again", "jobId", jobId)
+	return nil
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/lyrics"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetSections returns the song's lyrics sections of the given kind (all
+// kinds if empty) and number (all numbers if 0), in song order.
+func (r *StoreSongs) GetSections(ctx context.Context, songId int, kind string, number int) ([]entities.LyricsSection, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting lyrics sections")
+	var sections []entities.LyricsSection
+	err := r.db.WithContext(ctx).Select("id").First(&entities.Song{}, songId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, projectError.ErrSongNotFound
+	}
+	if err != nil {
+		log.Errorw("error with getting song", zap.Error(err))
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).Where("song_id = ?", songId)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if number != 0 {
+		query = query.Where("number = ?", number)
+	}
+	if err := query.Order("position").Find(&sections).Error; err != nil {
+		log.Errorw("error with getting lyrics sections", zap.Error(err))
+		return nil, err
+	}
+	log.Infow("lyrics sections are got", "songId", songId, "count", len(sections))
+	return sections, nil
+}
+
+// ImportSyncedLyrics replaces the song text with the lines of imported
+// synced lyrics and stores their timestamps. The text is marked as edited by
+// hand so enrichment does not overwrite it.
+func (r *StoreSongs) ImportSyncedLyrics(ctx context.Context, songId int, text string, offsetMs int, timings []entities.LyricsTiming) error {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started importing synced lyrics")
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Song{}).Where("id = ?", songId).Updates(map[string]interface{}{
+			"text":          text,
+			"field_sources": gorm.Expr("field_sources || ?::jsonb", entities.FieldSources{"text": entities.SourceManual}),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return projectError.ErrSongNotFound
+		}
+		if err := syncLyrics(tx, uint(songId), text); err != nil {
+			return err
+		}
+		if err := tx.Model(&entities.Song{}).Where("id = ?", songId).Update("lyrics_offset_ms", offsetMs).Error; err != nil {
+			return err
+		}
+		if len(timings) == 0 {
+			return nil
+		}
+		return tx.Create(&timings).Error
+	})
+	if err != nil {
+		log.Errorw("error with importing synced lyrics", zap.Error(err))
+		return err
+	}
+	log.Infow("synced lyrics are imported", "songId", songId, "timings", len(timings))
+	return nil
+}
+
+// GetSyncedLyrics returns the song with its line timestamps ordered by line.
+func (r *StoreSongs) GetSyncedLyrics(ctx context.Context, songId int) (entities.Song, []entities.LyricsTiming, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting synced lyrics")
+	var (
+		song    entities.Song
+		timings []entities.LyricsTiming
+	)
+	err := r.db.WithContext(ctx).First(&song, songId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return song, nil, projectError.ErrSongNotFound
+	}
+	if err != nil {
+		log.Errorw("error with getting song", zap.Error(err))
+		return song, nil, err
+	}
+	if err := r.db.WithContext(ctx).Where("song_id = ?", songId).Order("line").Find(&timings).Error; err != nil {
+		log.Errorw("error with getting lyrics timings", zap.Error(err))
+		return song, nil, err
+	}
+	log.Infow("synced lyrics are got", "songId", songId, "timings", len(timings))
+	return song, timings, nil
+}
+
+// syncLyrics keeps data derived from songs.text in sync with it: sections
+// are re-parsed and line timestamps, which no longer match the lines, are
+// dropped. It must run in the transaction that changes the text.
+func syncLyrics(tx *gorm.DB, songId uint, text string) error {
+	if err := tx.Where("song_id = ?", songId).Delete(&entities.LyricsTiming{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&entities.Song{}).Where("id = ?", songId).Update("lyrics_offset_ms", 0).Error; err != nil {
+		return err
+	}
+	return replaceSections(tx, songId, text)
+}
+
+func replaceSections(tx *gorm.DB, songId uint, text string) error {
+	if err := tx.Where("song_id = ?", songId).Delete(&entities.LyricsSection{}).Error; err != nil {
+		return err
+	}
+	parsed := lyrics.ParseSections(text)
+	if len(parsed) == 0 {
+		return nil
+	}
+	sections := make([]entities.LyricsSection, 0, len(parsed))
+	for _, section := range parsed {
+		sections = append(sections, entities.LyricsSection{
+			SongID:   songId,
+			Position: section.Position,
+			Kind:     section.Kind,
+			Number:   section.Number,
+			Text:     section.Text,
+		})
+	}
+	return tx.Create(&sections).Error
+}
+
+// sectionsBatch is how many songs backfillSections parses per round.
+const sectionsBatch = 500
+
+// backfillSections parses the sections of songs whose lyrics were stored
+// before sections were tracked, or before migration 0017 cleared them to be
+// parsed again. Songs are locked first, so a concurrent edit of the text
+// either waits or is what gets parsed. Lyrics without any section are
+// looked at again on every start, which is cheap since they are rare.
+func backfillSections(ctx context.Context, db *gorm.DB) error {
+	log := logger.LoggerFromContext(ctx)
+	total := 0
+	var lastId uint
+	for {
+		var songs []entities.Song
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Select("id", "text").
+				Where("text <> '' AND id > ?", lastId).
+				Where("NOT EXISTS (SELECT 1 FROM lyrics_sections WHERE lyrics_sections.song_id = songs.id)").
+				Order("id").
+				Limit(sectionsBatch).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Find(&songs).Error
+			if err != nil {
+				return err
+			}
+			for _, song := range songs {
+				if err := replaceSections(tx, song.ID, song.Text); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Errorw("error with backfilling lyrics sections", zap.Error(err))
+			return err
+		}
+		if len(songs) == 0 {
+			break
+		}
+		total += len(songs)
+		lastId = songs[len(songs)-1].ID
+	}
+	if total > 0 {
+		log.Infow("lyrics sections are backfilled", "count", total)
+	}
+	return nil
+}
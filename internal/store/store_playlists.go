@@ -0,0 +1,376 @@
+package store
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/logger"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// playlistColumns selects a playlist with its number of entries.
+const playlistColumns = "playlists.*, (SELECT count(*) FROM playlist_entries WHERE playlist_entries.playlist_id = playlists.id) AS song_count"
+
+type StorePlaylists struct {
+	db *gorm.DB
+}
+
+func NewStorePlaylists(db *gorm.DB) *StorePlaylists {
+	return &StorePlaylists{
+		db: db,
+	}
+}
+
+// InsertPlaylist stores the playlist with the songs of its entries, in
+// their order.
+func (r *StorePlaylists) InsertPlaylist(ctx context.Context, playlist entities.Playlist) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("inserting playlist")
+	entries := playlist.Entries
+	playlist.Entries = nil
+	playlist.Version = 1
+	var inserted entities.Playlist
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&playlist).Error; err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			for i := range entries {
+				entries[i].PlaylistID = playlist.ID
+				entries[i].Position = i + 1
+			}
+			if err := tx.Create(&entries).Error; err != nil {
+				return err
+			}
+		}
+		var err error
+		inserted, err = getPlaylist(tx, playlist.ID)
+		return err
+	})
+	if err != nil {
+		log.Errorw("error with inserting playlist", zap.Error(err))
+		return inserted, err
+	}
+	log.Infow("playlist is inserted", "playlistId", inserted.ID)
+	return inserted, nil
+}
+
+// GetPlaylists lists playlists by name without their entries, and the
+// total number of playlists.
+func (r *StorePlaylists) GetPlaylists(ctx context.Context, limit, offset int) ([]entities.Playlist, int64, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting playlists")
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&entities.Playlist{}).Count(&total).Error; err != nil {
+		log.Errorw("error with counting playlists", zap.Error(err))
+		return nil, 0, err
+	}
+	var playlists []entities.Playlist
+	err := r.db.WithContext(ctx).
+		Select(playlistColumns).
+		Order("lower(name), id").
+		Offset((offset - 1) * limit).
+		Limit(limit).
+		Find(&playlists).Error
+	if err != nil {
+		log.Errorw("error with getting playlists", zap.Error(err))
+		return nil, 0, err
+	}
+	log.Infow("playlists are got", "count", len(playlists))
+	return playlists, total, nil
+}
+
+// GetPlaylist returns the playlist with its entries in order.
+func (r *StorePlaylists) GetPlaylist(ctx context.Context, playlistId int) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting playlist")
+	playlist, err := getPlaylist(r.db.WithContext(ctx), uint(playlistId))
+	if err != nil {
+		if !errors.Is(err, projectError.ErrPlaylistNotFound) {
+			log.Errorw("error with getting playlist", zap.Error(err))
+		}
+		return playlist, err
+	}
+	log.Infow("playlist is got", "playlistId", playlistId)
+	return playlist, nil
+}
+
+func (r *StorePlaylists) UpdatePlaylist(ctx context.Context, playlistId int, update entities.PlaylistUpdate) (entities.Playlist, error) {
+	return r.editPlaylist(ctx, "updating playlist", playlistId, update.Version, 0, func(tx *gorm.DB, playlist *entities.Playlist) error {
+		updates := make(map[string]interface{})
+		if update.Name != nil {
+			updates["name"] = *update.Name
+		}
+		if update.Description != nil {
+			updates["description"] = *update.Description
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&entities.Playlist{}).Where("id = ?", playlistId).Updates(updates).Error
+	})
+}
+
+func (r *StorePlaylists) DeletePlaylist(ctx context.Context, playlistId int, version *int) error {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started deleting playlist")
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		playlist, err := lockPlaylist(tx, playlistId, version)
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&playlist).Error
+	})
+	if err != nil {
+		log.Errorw("error with deleting playlist", zap.Error(err))
+		return err
+	}
+	log.Infow("playlist is deleted", "playlistId", playlistId)
+	return nil
+}
+
+// AddEntry inserts the song at position, shifting the entries from there
+// down. A position past the end, or zero, appends.
+func (r *StorePlaylists) AddEntry(ctx context.Context, playlistId int, songId uint, position int, version *int) (entities.Playlist, error) {
+	return r.editPlaylist(ctx, "adding playlist entry", playlistId, version, songId, func(tx *gorm.DB, playlist *entities.Playlist) error {
+		size, err := countEntries(tx, playlist.ID)
+		if err != nil {
+			return err
+		}
+		if position < 1 || position > size+1 {
+			position = size + 1
+		}
+		err = tx.Model(&entities.PlaylistEntry{}).
+			Where("playlist_id = ? AND position >= ?", playlist.ID, position).
+			Update("position", gorm.Expr("position + 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&entities.PlaylistEntry{
+			PlaylistID: playlist.ID,
+			SongID:     songId,
+			Position:   position,
+		}).Error
+	})
+}
+
+// MoveEntry moves the entry to position, shifting the entries between its
+// old and new place. Positions out of range move it to the start or the end.
+func (r *StorePlaylists) MoveEntry(ctx context.Context, playlistId int, entryId uint, position int, version *int) (entities.Playlist, error) {
+	return r.editPlaylist(ctx, "moving playlist entry", playlistId, version, 0, func(tx *gorm.DB, playlist *entities.Playlist) error {
+		entry, err := getEntry(tx, playlist.ID, entryId)
+		if err != nil {
+			return err
+		}
+		size, err := countEntries(tx, playlist.ID)
+		if err != nil {
+			return err
+		}
+		position = min(max(position, 1), size)
+		entries := tx.Model(&entities.PlaylistEntry{}).Where("playlist_id = ?", playlist.ID)
+		switch {
+		case position < entry.Position:
+			err = entries.Where("position >= ? AND position < ?", position, entry.Position).
+				Update("position", gorm.Expr("position + 1")).Error
+		case position > entry.Position:
+			err = entries.Where("position > ? AND position <= ?", entry.Position, position).
+				Update("position", gorm.Expr("position - 1")).Error
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&entry).Update("position", position).Error
+	})
+}
+
+// RemoveEntry removes the entry and closes the gap it leaves.
+func (r *StorePlaylists) RemoveEntry(ctx context.Context, playlistId int, entryId uint, version *int) (entities.Playlist, error) {
+	return r.editPlaylist(ctx, "removing playlist entry", playlistId, version, 0, func(tx *gorm.DB, playlist *entities.Playlist) error {
+		entry, err := getEntry(tx, playlist.ID, entryId)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&entry).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entities.PlaylistEntry{}).
+			Where("playlist_id = ? AND position > ?", playlist.ID, entry.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// ReorderPlaylist puts the entries in the order of entryIds, which must list
+// every entry of the playlist exactly once; otherwise the playlist changed
+// since the client read it and ErrEntriesMismatch is returned.
+func (r *StorePlaylists) ReorderPlaylist(ctx context.Context, playlistId int, entryIds []uint, version *int) (entities.Playlist, error) {
+	return r.editPlaylist(ctx, "reordering playlist", playlistId, version, 0, func(tx *gorm.DB, playlist *entities.Playlist) error {
+		var current []uint
+		err := tx.Model(&entities.PlaylistEntry{}).
+			Where("playlist_id = ?", playlist.ID).
+			Pluck("id", &current).Error
+		if err != nil {
+			return err
+		}
+		if len(current) != len(entryIds) {
+			return projectError.ErrEntriesMismatch
+		}
+		positions := make(map[uint]int, len(entryIds))
+		for i, id := range entryIds {
+			positions[id] = i + 1
+		}
+		if len(positions) != len(entryIds) {
+			return projectError.ErrEntriesMismatch
+		}
+		for _, id := range current {
+			if _, ok := positions[id]; !ok {
+				return projectError.ErrEntriesMismatch
+			}
+		}
+		for id, position := range positions {
+			if err := tx.Model(&entities.PlaylistEntry{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// editPlaylist runs edit in a transaction holding the playlist row lock, so
+// edits of one playlist are serialized, then bumps the version and returns
+// the playlist as edited. A stale version fails with ErrPlaylistChanged.
+// songId, if not zero, is the song edit adds; it is locked against deletion
+// before the playlist, the order DeleteSong takes the locks in.
+func (r *StorePlaylists) editPlaylist(ctx context.Context, action string, playlistId int, version *int, songId uint, edit func(tx *gorm.DB, playlist *entities.Playlist) error) (entities.Playlist, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started " + action)
+	var edited entities.Playlist
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if songId != 0 {
+			var song entities.Song
+			err := tx.Clauses(clause.Locking{Strength: "KEY SHARE"}).Select("id").First(&song, songId).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return projectError.ErrSongNotFound
+			}
+			if err != nil {
+				return err
+			}
+		}
+		playlist, err := lockPlaylist(tx, playlistId, version)
+		if err != nil {
+			return err
+		}
+		if err := edit(tx, &playlist); err != nil {
+			return err
+		}
+		err = tx.Model(&playlist).Updates(map[string]interface{}{
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": gorm.Expr("now()"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		edited, err = getPlaylist(tx, playlist.ID)
+		return err
+	})
+	if err != nil {
+		log.Errorw("error with "+action, zap.Error(err))
+		return edited, err
+	}
+	log.Infow("playlist is changed", "playlistId", playlistId, "version", edited.Version)
+	return edited, nil
+}
+
+// lockPlaylist locks the playlist row for the rest of the transaction and
+// checks its version, if given.
+func lockPlaylist(tx *gorm.DB, playlistId int, version *int) (entities.Playlist, error) {
+	var playlist entities.Playlist
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&playlist, playlistId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return playlist, projectError.ErrPlaylistNotFound
+	}
+	if err != nil {
+		return playlist, err
+	}
+	if version != nil && *version != playlist.Version {
+		return playlist, projectError.ErrPlaylistChanged
+	}
+	return playlist, nil
+}
+
+func getPlaylist(db *gorm.DB, playlistId uint) (entities.Playlist, error) {
+	var playlist entities.Playlist
+	err := db.Select(playlistColumns).First(&playlist, playlistId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return playlist, projectError.ErrPlaylistNotFound
+	}
+	if err != nil {
+		return playlist, err
+	}
+	playlist.Entries = make([]entities.PlaylistEntry, 0)
+	err = db.Model(&entities.PlaylistEntry{}).
+		Select("playlist_entries.*, songs.group_name, songs.song, songs.link").
+		Joins("JOIN songs ON songs.id = playlist_entries.song_id").
+		Where("playlist_entries.playlist_id = ?", playlistId).
+		Order("playlist_entries.position").
+		Find(&playlist.Entries).Error
+	return playlist, err
+}
+
+func getEntry(tx *gorm.DB, playlistId, entryId uint) (entities.PlaylistEntry, error) {
+	var entry entities.PlaylistEntry
+	err := tx.Where("playlist_id = ?", playlistId).First(&entry, entryId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entry, projectError.ErrEntryNotFound
+	}
+	return entry, err
+}
+
+func countEntries(tx *gorm.DB, playlistId uint) (int, error) {
+	var size int64
+	err := tx.Model(&entities.PlaylistEntry{}).Where("playlist_id = ?", playlistId).Count(&size).Error
+	return int(size), err
+}
+
+// removeSongFromPlaylists deletes the entries of the song, closes the gaps
+// they leave and bumps the versions of the playlists. It locks the song
+// first, so no entry of it can be added meanwhile, then the playlists in id
+// order. It must run inside a transaction that deletes the song.
+func removeSongFromPlaylists(tx *gorm.DB, songId int) error {
+	var song entities.Song
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", songId).Find(&song).Error
+	if err != nil {
+		return err
+	}
+	var playlistIds []uint
+	err = tx.Model(&entities.Playlist{}).
+		Where("id IN (SELECT playlist_id FROM playlist_entries WHERE song_id = ?)", songId).
+		Order("id").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Pluck("id", &playlistIds).Error
+	if err != nil || len(playlistIds) == 0 {
+		return err
+	}
+	if err := tx.Where("song_id = ?", songId).Delete(&entities.PlaylistEntry{}).Error; err != nil {
+		return err
+	}
+	err = tx.Exec(`UPDATE playlist_entries SET position = renumbered.position
+		FROM (
+			SELECT id, row_number() OVER (PARTITION BY playlist_id ORDER BY position) AS position
+			FROM playlist_entries
+			WHERE playlist_id IN ?
+		) AS renumbered
+		WHERE playlist_entries.id = renumbered.id AND playlist_entries.position <> renumbered.position`, playlistIds).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&entities.Playlist{}).Where("id IN ?", playlistIds).Updates(map[string]interface{}{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": gorm.Expr("now()"),
+	}).Error
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"effectiveMobile/internal/entities"
+	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/translit"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// searchConfigs are the text search configurations search_vector is built
+// with; see migration 0006.
+var searchConfigs = map[string]bool{
+	"english": true,
+	"russian": true,
+}
+
+// SearchLyrics runs a full-text search over song titles and lyrics. The
+// query is parsed with every config in languages and the results are ranked
+// with ts_rank; headline holds the matched lyric fragments from ts_headline
+// using headlineConfig. total is the number of all matching songs.
+func (r *StoreSongs) SearchLyrics(ctx context.Context, query string, languages []string, headlineConfig string, limit, offset int) ([]entities.SearchResult, int64, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started searching lyrics")
+
+	tsQueries := make([]string, 0, len(languages))
+	args := make([]interface{}, 0, len(languages))
+	for _, language := range languages {
+		if !searchConfigs[language] {
+			continue
+		}
+		tsQueries = append(tsQueries, "websearch_to_tsquery('"+language+"', ?)")
+		args = append(args, query)
+	}
+	if !searchConfigs[headlineConfig] {
+		headlineConfig = "simple"
+	}
+
+	from := "songs, (SELECT " + strings.Join(tsQueries, " || ") + " AS q) AS search"
+	var total int64
+	err := r.db.WithContext(ctx).
+		Table(from, args...).
+		Where("songs.search_vector @@ search.q").
+		Count(&total).Error
+	if err != nil {
+		log.Errorw("error with counting search results", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var results []entities.SearchResult
+	err = r.db.WithContext(ctx).
+		Table(from, args...).
+		Select("songs.*, ts_rank(songs.search_vector, search.q) AS rank, " +
+			"ts_headline('" + headlineConfig + "', coalesce(songs.text, ''), search.q, 'MaxFragments=2, MaxWords=15, MinWords=5') AS headline").
+		Where("songs.search_vector @@ search.q").
+		Order("rank DESC, songs.id").
+		Offset((offset - 1) * limit).
+		Limit(limit).
+		Scan(&results).Error
+	if err != nil {
+		log.Errorw("error with searching lyrics", zap.Error(err))
+		return nil, 0, err
+	}
+	log.Infow("lyrics are searched", "count", len(results), "total", total)
+	return results, total, nil
+}
+
+// fuzzyColumns are the trigram-indexed search key columns per searchable
+// field; see migration 0008.
+var fuzzyColumns = map[string]string{
+	"group": "songs.group_key",
+	"song":  "songs.song_key",
+}
+
+// FuzzySearch finds songs whose fields are trigram-similar to query, case,
+// accent and script insensitively, ordered by the best similarity among
+// fields. total is the number of all matching songs.
+func (r *StoreSongs) FuzzySearch(ctx context.Context, query string, fields []string, threshold float64, limit, offset int) ([]entities.FuzzyResult, int64, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started fuzzy searching songs")
+
+	conditions := make([]string, 0, len(fields))
+	scores := make([]string, 0, len(fields))
+	for _, field := range fields {
+		column, ok := fuzzyColumns[field]
+		if !ok {
+			continue
+		}
+		conditions = append(conditions, column+" % search.q")
+		scores = append(scores, "similarity("+column+", search.q)")
+	}
+	score := scores[0]
+	if len(scores) > 1 {
+		score = "greatest(" + strings.Join(scores, ", ") + ")"
+	}
+
+	var results []entities.FuzzyResult
+	var total int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// % compares against this setting, which lets it use the trigram indexes
+		err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)",
+			strconv.FormatFloat(threshold, 'f', -1, 64)).Error
+		if err != nil {
+			return err
+		}
+		from := "songs, (SELECT ?::text AS q) AS search"
+		key := translit.Key(query)
+		err = tx.Table(from, key).
+			Where(strings.Join(conditions, " OR ")).
+			Count(&total).Error
+		if err != nil {
+			return err
+		}
+		return tx.Table(from, key).
+			Select("songs.*, " + score + " AS score").
+			Where(strings.Join(conditions, " OR ")).
+			Order("score DESC, songs.id").
+			Offset((offset - 1) * limit).
+			Limit(limit).
+			Scan(&results).Error
+	})
+	if err != nil {
+		log.Errorw("error with fuzzy searching songs", zap.Error(err))
+		return nil, 0, err
+	}
+	log.Infow("songs are fuzzy searched", "count", len(results), "total", total)
+	return results, total, nil
+}
@@ -3,11 +3,20 @@ package store
 import (
 	"context"
 	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"effectiveMobile/internal/filter"
 	"effectiveMobile/internal/logger"
+	"effectiveMobile/internal/translit"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StoreSongs struct {
@@ -23,7 +32,10 @@ func NewStoreSongs(db *gorm.DB) *StoreSongs {
 func (r *StoreSongs) InsertSong(ctx context.Context, req entities.Song) (int, error) {
 	log := logger.LoggerFromContext(ctx)
 	log.Info("inserting song")
-	if err := r.db.Create(&req).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return insertSong(tx, &req)
+	})
+	if err != nil {
 		log.Errorw("error with inserting song", zap.Error(err))
 		return 0, err
 	}
@@ -31,19 +43,182 @@ func (r *StoreSongs) InsertSong(ctx context.Context, req entities.Song) (int, er
 	return int(req.ID), nil
 }
 
-func (r *StoreSongs) GetSongs(ctx context.Context, filters entities.Song, limit, offset int) ([]entities.Song, error) {
+// insertSong creates song with its artist, album track and lyrics sections.
+func insertSong(tx *gorm.DB, song *entities.Song) error {
+	if err := createSong(tx, song); err != nil {
+		return err
+	}
+	if err := linkAlbum(tx, song.ID, song.ArtistID, song.Album); err != nil {
+		return err
+	}
+	return syncLyrics(tx, song.ID, song.Text)
+}
+
+// createSong creates the song row and links its artist. A song of the same
+// name, see SongNameKey, is a *SongExistsError, and then nothing is written.
+// Writes of one name take turns on an advisory lock, so the check holds
+// even before the unique index on name_key exists.
+func createSong(tx *gorm.DB, song *entities.Song) error {
+	if err := lockSongName(tx, SongNameKey(song.GroupName, song.Song)); err != nil {
+		return err
+	}
+	existing, err := findSong(tx, song.GroupName, song.Song)
+	if err == nil {
+		return &projectError.SongExistsError{ID: existing.ID}
+	}
+	if !errors.Is(err, projectError.ErrSongNotFound) {
+		return err
+	}
+	if err := linkArtist(tx, song); err != nil {
+		return err
+	}
+	// a song renamed meanwhile may still take the name
+	return songNameConflict(tx.Create(song).Error)
+}
+
+func (r *StoreSongs) GetSongs(ctx context.Context, filters entities.SongFilters, sort []entities.SortKey, limit, offset int) ([]entities.Song, error) {
 	log := logger.LoggerFromContext(ctx)
 	log.Info("started getting songs")
 
 	var songs []entities.Song
-	query := r.db.Model(&entities.Song{})
+	query := orderSongs(r.filterSongs(ctx, filters), sort, false).Offset((offset - 1) * limit).Limit(limit)
+	if err := query.Find(&songs).Error; err != nil {
+		log.Errorw("error with getting songs", zap.Error(err))
+		return nil, err
+	}
+
+	log.Infow("songs are got", "count", len(songs))
+	return songs, nil
+}
+
+// GetSongsPage returns up to limit songs in sort order, id breaking ties,
+// that follow cursor, or precede it for a backward cursor, and whether there
+// are more songs beyond the page in that direction. A nil cursor starts from
+// the first song. A cursor that does not fit sort is ErrIncorrectRequest.
+func (r *StoreSongs) GetSongsPage(ctx context.Context, filters entities.SongFilters, sort []entities.SortKey, cursor *entities.SongCursor, limit int) ([]entities.Song, bool, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting songs page")
+
+	query := r.filterSongs(ctx, filters)
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		var err error
+		query, err = seekSongs(query, sort, cursor.Values, backward)
+		if err != nil {
+			log.Errorw("error with cursor values", zap.Error(err))
+			return nil, false, err
+		}
+	}
+	query = orderSongs(query, sort, backward)
+
+	var songs []entities.Song
+	// one extra row tells whether another page follows
+	if err := query.Limit(limit + 1).Find(&songs).Error; err != nil {
+		log.Errorw("error with getting songs page", zap.Error(err))
+		return nil, false, err
+	}
+	more := len(songs) > limit
+	if more {
+		songs = songs[:limit]
+	}
+	if backward {
+		slices.Reverse(songs)
+	}
+
+	log.Infow("songs page is got", "count", len(songs), "more", more)
+	return songs, more, nil
+}
+
+// StreamSongs passes every song matching filters, in sort order with id
+// breaking ties, to fn, batch songs at a time. The songs are read through a
+// server-side cursor in one read-only transaction, so they are a consistent
+// snapshot and memory does not grow with their number. An error of fn stops
+// the stream and is returned.
+func (r *StoreSongs) StreamSongs(ctx context.Context, filters entities.SongFilters, sort []entities.SortKey, batch int, fn func([]entities.Song) error) error {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started streaming songs")
+	stmt := orderSongs(r.filterSongs(ctx, filters), sort, false).
+		Session(&gorm.Session{DryRun: true}).
+		Find(&[]entities.Song{}).Statement
+	count := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION READ ONLY").Error; err != nil {
+			return err
+		}
+		// the query is run as built, placeholders and all, so it skips gorm
+		_, err := tx.Statement.ConnPool.ExecContext(ctx, "DECLARE songs_stream NO SCROLL CURSOR FOR "+stmt.SQL.String(), stmt.Vars...)
+		if err != nil {
+			return err
+		}
+		for {
+			var songs []entities.Song
+			if err := tx.Raw(fmt.Sprintf("FETCH FORWARD %d FROM songs_stream", batch)).Scan(&songs).Error; err != nil {
+				return err
+			}
+			if len(songs) == 0 {
+				return nil
+			}
+			count += len(songs)
+			if err := fn(songs); err != nil {
+				return err
+			}
+			if len(songs) < batch {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		log.Errorw("error with streaming songs", "count", count, zap.Error(err))
+		return err
+	}
+	log.Infow("songs are streamed", "count", count)
+	return nil
+}
+
+// CountSongs counts the songs matching filters. With estimate set and no
+// filters it returns the planner's row estimate instead, which is cheap on
+// big tables; the second result tells whether the count is an estimate.
+func (r *StoreSongs) CountSongs(ctx context.Context, filters entities.SongFilters, estimate bool) (int64, bool, error) {
+	log := logger.LoggerFromContext(ctx)
+	if estimate && filters.IsZero() {
+		var estimated int64
+		err := r.db.WithContext(ctx).
+			Raw("SELECT reltuples::bigint FROM pg_class WHERE oid = 'songs'::regclass").
+			Scan(&estimated).Error
+		if err != nil {
+			log.Errorw("error with estimating songs count", zap.Error(err))
+			return 0, false, err
+		}
+		// reltuples is -1 until the table is first vacuumed or analyzed
+		if estimated >= 0 {
+			return estimated, true, nil
+		}
+	}
+	var total int64
+	if err := r.filterSongs(ctx, filters).Count(&total).Error; err != nil {
+		log.Errorw("error with counting songs", zap.Error(err))
+		return 0, false, err
+	}
+	return total, false, nil
+}
+
+// filterSongs applies the GetSongs filters. Group and song are matched by
+// their search keys, so either script finds both spellings.
+func (r *StoreSongs) filterSongs(ctx context.Context, filters entities.SongFilters) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&entities.Song{})
+
+	for column, value := range map[string]string{
+		"group_key": filters.GroupName,
+		"song_key":  filters.Song,
+	} {
+		if key := translit.Key(value); key != "" {
+			query = query.Where(fmt.Sprintf("%s LIKE ?", column), "%"+key+"%")
+		}
+	}
 
 	filterMap := map[string]string{
-		"group_name":   filters.GroupName,
-		"song":         filters.Song,
-		"release_date": filters.ReleaseDate,
-		"text":         filters.Text,
-		"link":         filters.Link,
+		"text": filters.Text,
+		"link": filters.Link,
 	}
 
 	for field, value := range filterMap {
@@ -52,20 +227,31 @@ func (r *StoreSongs) GetSongs(ctx context.Context, filters entities.Song, limit,
 		}
 	}
 
-	query = query.Offset((offset - 1) * limit).Limit(limit)
-	if err := query.Find(&songs).Error; err != nil {
-		log.Errorw("error with getting songs", zap.Error(err))
-		return nil, err
+	if !filters.ReleasedFrom.IsZero() {
+		query = query.Where("release_date >= ?", filters.ReleasedFrom)
 	}
-
-	log.Infow("songs are got", "count", len(songs))
-	return songs, nil
+	if !filters.ReleasedTo.IsZero() {
+		query = query.Where("release_date <= ?", filters.ReleasedTo)
+	}
+	if filters.ArtistID != 0 {
+		query = query.Where("artist_id = ?", filters.ArtistID)
+	}
+	if filters.Expr != nil {
+		query = query.Where(filter.Clause(filters.Expr))
+	}
+	return query
 }
 
 func (r *StoreSongs) DeleteSong(ctx context.Context, songId int) error {
 	log := logger.LoggerFromContext(ctx)
 	log.Info("started deleting song")
-	if err := r.db.Delete(&entities.Song{}, songId).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := removeSongFromPlaylists(tx, songId); err != nil {
+			return err
+		}
+		return tx.Delete(&entities.Song{}, songId).Error
+	})
+	if err != nil {
 		log.Errorw("error with deleting song", zap.Error(err))
 		return err
 	}
@@ -73,6 +259,41 @@ func (r *StoreSongs) DeleteSong(ctx context.Context, songId int) error {
 	return nil
 }
 
+// FindSong returns the first song with the group and song name, compared by their
+// translit keys like the group and song filters do. Names without letters,
+// whose keys are empty, must match exactly.
+func (r *StoreSongs) FindSong(ctx context.Context, group, song string) (entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	found, err := findSong(r.db.WithContext(ctx), group, song)
+	if err != nil && !errors.Is(err, projectError.ErrSongNotFound) {
+		log.Errorw("error with finding song", zap.Error(err))
+	}
+	return found, err
+}
+
+// SongNameKey is the group and song name as FindSong compares them: two
+// names with the same key are the same song. It must build songs.name_key,
+// see migration 0015, which is unique once duplicates are merged.
+func SongNameKey(group, song string) string {
+	key := func(name string) string {
+		if key := translit.Key(name); key != "" {
+			return key
+		}
+		// btrim trims spaces only
+		return "=" + strings.ToLower(strings.Trim(name, " "))
+	}
+	return key(group) + "/" + key(song)
+}
+
+func findSong(db *gorm.DB, group, song string) (entities.Song, error) {
+	var found entities.Song
+	err := db.Where("name_key = ?", SongNameKey(group, song)).Order("id").First(&found).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return found, projectError.ErrSongNotFound
+	}
+	return found, err
+}
+
 func (r *StoreSongs) GetTextSong(ctx context.Context, songId int) (string, error) {
 	log := logger.LoggerFromContext(ctx)
 	log.Info("started getting text song")
@@ -88,10 +309,368 @@ func (r *StoreSongs) GetTextSong(ctx context.Context, songId int) (string, error
 func (r *StoreSongs) UpdateSong(ctx context.Context, songId int, song entities.SongUpdate) error {
 	log := logger.LoggerFromContext(ctx)
 	log.Info("started updating song")
-	if err := r.db.Model(&entities.Song{}).Where("id = ?", songId).Updates(song).Error; err != nil {
+	updates := make(map[string]interface{})
+	manual := make(entities.FieldSources)
+	if song.GroupName != nil {
+		updates["group_name"] = *song.GroupName
+	}
+	if song.Song != nil {
+		updates["song"] = *song.Song
+		updates["song_key"] = translit.Key(*song.Song)
+	}
+	for _, field := range enrichedFields {
+		if value := field.update(song); value != nil {
+			updates[field.column] = value
+			manual[field.name] = entities.SourceManual
+		}
+	}
+	clearReleaseDateRaw(updates)
+	if len(updates) == 0 {
+		log.Infow("nothing to update", "songId", songId)
+		return nil
+	}
+	if len(manual) > 0 {
+		updates["field_sources"] = gorm.Expr("field_sources || ?::jsonb", manual)
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if song.GroupName != nil {
+			artistId, name, err := ensureArtist(tx, *song.GroupName)
+			if err != nil {
+				return err
+			}
+			if artistId != nil {
+				updates["group_name"] = name
+			}
+			updates["artist_id"] = artistId
+			updates["group_key"] = translit.Key(name)
+		}
+		if song.GroupName != nil || song.Song != nil {
+			if err := checkSongName(tx, songId, updates); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&entities.Song{}).Where("id = ?", songId).Updates(updates).Error; err != nil {
+			return songNameConflict(err)
+		}
+		if song.Text == nil {
+			return nil
+		}
+		return syncLyrics(tx, uint(songId), *song.Text)
+	})
+	if err != nil {
 		log.Errorw("error with updating song", zap.Error(err))
 		return err
 	}
 	log.Infow("song is updated", "songId", songId)
 	return nil
 }
+
+// checkSongName fails with a *SongExistsError if the song renamed as updates
+// say would have the name of another song.
+func checkSongName(tx *gorm.DB, songId int, updates map[string]interface{}) error {
+	var current entities.Song
+	err := tx.Select("id", "group_name", "song").Take(&current, songId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return projectError.ErrSongNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if name, ok := updates["group_name"].(string); ok {
+		current.GroupName = name
+	}
+	if name, ok := updates["song"].(string); ok {
+		current.Song = name
+	}
+	if err := lockSongName(tx, SongNameKey(current.GroupName, current.Song)); err != nil {
+		return err
+	}
+	existing, err := findSong(tx, current.GroupName, current.Song)
+	if err == nil && existing.ID != current.ID {
+		return &projectError.SongExistsError{ID: existing.ID}
+	}
+	if err != nil && !errors.Is(err, projectError.ErrSongNotFound) {
+		return err
+	}
+	return nil
+}
+
+// lockSongName waits for the transactions writing a song named key, see
+// SongNameKey, and holds them off until tx ends.
+func lockSongName(tx *gorm.DB, key string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error
+}
+
+// lockSongNames takes the locks of lockSongName for all keys, in one order,
+// so that two transactions locking several names never wait for each other.
+// keys are sorted in place.
+func lockSongNames(tx *gorm.DB, keys []string) error {
+	slices.Sort(keys)
+	for _, key := range slices.Compact(keys) {
+		if err := lockSongName(tx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// songNameConflict turns the violation of the unique song name index
+// into ErrSongExists; checks made before the write cannot see songs written
+// meanwhile.
+func songNameConflict(err error) error {
+	var pgErr *pgconn.PgError
+	// 23505 is unique_violation
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == songNameIndex {
+		return fmt.Errorf("%w: %s", projectError.ErrSongExists, pgErr.Detail)
+	}
+	return err
+}
+
+func (r *StoreSongs) GetSong(ctx context.Context, songId int) (entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started getting song")
+	var song entities.Song
+	err := r.db.WithContext(ctx).First(&song, songId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return song, projectError.ErrSongNotFound
+	}
+	if err != nil {
+		log.Errorw("error with getting song", zap.Error(err))
+		return song, err
+	}
+	log.Infow("song is got", "songId", songId)
+	return song, nil
+}
+
+// MergeEnrichment stores freshly enriched fields, skipping the ones edited
+// by hand, and returns the updated song.
+func (r *StoreSongs) MergeEnrichment(ctx context.Context, songId int, details entities.Song) (entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info("started merging enriched song")
+	var song entities.Song
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := mergeEnrichment(tx, uint(songId), details); err != nil {
+			return err
+		}
+		return tx.First(&song, songId).Error
+	})
+	if err != nil {
+		log.Errorw("error with merging enriched song", zap.Error(err))
+		return song, err
+	}
+	log.Infow("enriched song is merged", "songId", songId, "sources", song.FieldSources)
+	return song, nil
+}
+
+// GetStaleSongs takes up to limit enriched songs the refresh scheduler last
+// took before olderThan, the longest waiting first, and marks them taken
+// now. That counts whether their refresh works or not, so songs that keep
+// failing wait their turn like the others. Songs another replica is taking
+// are skipped.
+func (r *StoreSongs) GetStaleSongs(ctx context.Context, olderThan time.Time, limit int) ([]entities.Song, error) {
+	log := logger.LoggerFromContext(ctx)
+	var songs []entities.Song
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND refresh_attempted_at < ?", entities.SongStatusEnriched, olderThan).
+			Order("refresh_attempted_at, id").
+			Limit(limit).
+			Find(&songs).Error
+		if err != nil || len(songs) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(songs))
+		for _, song := range songs {
+			ids = append(ids, song.ID)
+		}
+		return tx.Model(&entities.Song{}).Where("id IN ?", ids).Update("refresh_attempted_at", gorm.Expr("now()")).Error
+	})
+	if err != nil {
+		log.Errorw("error with getting stale songs", zap.Error(err))
+		return nil, err
+	}
+	return songs, nil
+}
+
+// enrichedField describes a song field filled by enrichment: its json name,
+// used as the key in field_sources, and its column. value returns nil for
+// an empty field and update nil for a field the update leaves alone.
+type enrichedField struct {
+	name   string
+	column string
+	value  func(song entities.Song) interface{}
+	update func(song entities.SongUpdate) interface{}
+}
+
+var enrichedFields = []enrichedField{
+	{
+		name:   "releaseDate",
+		column: "release_date",
+		value: func(song entities.Song) interface{} {
+			if song.ReleaseDate.IsZero() {
+				return nil
+			}
+			return song.ReleaseDate
+		},
+		update: func(song entities.SongUpdate) interface{} {
+			if song.ReleaseDate == nil {
+				return nil
+			}
+			return *song.ReleaseDate
+		},
+	},
+	{
+		name:   "text",
+		column: "text",
+		value:  func(song entities.Song) interface{} { return nonEmpty(song.Text) },
+		update: func(song entities.SongUpdate) interface{} { return deref(song.Text) },
+	},
+	{
+		name:   "link",
+		column: "link",
+		value:  func(song entities.Song) interface{} { return nonEmpty(song.Link) },
+		update: func(song entities.SongUpdate) interface{} { return deref(song.Link) },
+	},
+}
+
+// clearReleaseDateRaw drops the unparsed release date text of a song whose
+// updates set a release date.
+func clearReleaseDateRaw(updates map[string]interface{}) {
+	if _, ok := updates["release_date"]; ok {
+		updates["release_date_raw"] = nil
+	}
+}
+
+func nonEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func deref(value *string) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// mergeEnrichment writes the non-empty fields of details whose current
+// source is not manual, records their sources, puts the song on the album
+// of details and marks the song enriched.
+// It locks the song row, so it must run inside a transaction.
+func mergeEnrichment(tx *gorm.DB, songId uint, details entities.Song) error {
+	var current entities.Song
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, songId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return projectError.ErrSongNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	sources := make(entities.FieldSources, len(current.FieldSources))
+	for field, source := range current.FieldSources {
+		sources[field] = source
+	}
+	updates := make(map[string]interface{})
+	for _, field := range enrichedFields {
+		value := field.value(details)
+		if value == nil || sources[field.name] == entities.SourceManual {
+			continue
+		}
+		updates[field.column] = value
+		sources[field.name] = details.FieldSources[field.name]
+	}
+	clearReleaseDateRaw(updates)
+	updates["field_sources"] = sources
+	updates["enriched_at"] = time.Now()
+	updates["refresh_attempted_at"] = updates["enriched_at"]
+	updates["status"] = entities.SongStatusEnriched
+	if err := tx.Model(&entities.Song{}).Where("id = ?", songId).Updates(updates).Error; err != nil {
+		return err
+	}
+	if err := linkAlbum(tx, songId, current.ArtistID, details.Album); err != nil {
+		return err
+	}
+	if text, ok := updates["text"].(string); ok {
+		return syncLyrics(tx, songId, text)
+	}
+	return nil
+}
+
+// setSearchKeys fills the translit keys of a song about to be inserted.
+func setSearchKeys(song *entities.Song) {
+	song.GroupKey = translit.Key(song.GroupName)
+	song.SongKey = translit.Key(song.Song)
+}
+
+// searchKeysBatch is how many songs backfillSearchKeys updates per round.
+const searchKeysBatch = 500
+
+// backfillSearchKeys computes the translit keys of songs written before
+// migration 0008 or by older replicas. Once the unique index on name_key
+// exists, a song whose name another song has already keeps no keys and is
+// reported; merge the two to fill them.
+func backfillSearchKeys(ctx context.Context, db *gorm.DB) error {
+	log := logger.LoggerFromContext(ctx)
+	unique, err := hasSongNameIndex(ctx, db)
+	if err != nil {
+		log.Errorw("error with checking song name index", zap.Error(err))
+		return err
+	}
+	total := 0
+	var collisions []string
+	var lastId uint
+	for {
+		var songs []entities.Song
+		err := db.WithContext(ctx).Select("id", "group_name", "song").
+			Where("(group_key IS NULL OR song_key IS NULL) AND id > ?", lastId).
+			Order("id").
+			Limit(searchKeysBatch).
+			Find(&songs).Error
+		if err != nil {
+			log.Errorw("error with getting songs without search keys", zap.Error(err))
+			return err
+		}
+		if len(songs) == 0 {
+			break
+		}
+		err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for _, song := range songs {
+				if unique {
+					existing, err := findSong(tx, song.GroupName, song.Song)
+					if err == nil {
+						collisions = append(collisions, fmt.Sprintf("%d (name of %d)", song.ID, existing.ID))
+						continue
+					}
+					if !errors.Is(err, projectError.ErrSongNotFound) {
+						return err
+					}
+				}
+				err := tx.Model(&entities.Song{}).Where("id = ?", song.ID).Updates(map[string]interface{}{
+					"group_key": translit.Key(song.GroupName),
+					"song_key":  translit.Key(song.Song),
+				}).Error
+				if err != nil {
+					return err
+				}
+				total++
+			}
+			return nil
+		})
+		if err != nil {
+			log.Errorw("error with backfilling search keys", zap.Error(err))
+			return err
+		}
+		lastId = songs[len(songs)-1].ID
+	}
+	if total > 0 {
+		log.Infow("search keys are backfilled", "count", total)
+	}
+	if len(collisions) > 0 {
+		log.Warnw("songs have the names of other songs and are left without search keys, merge them",
+			"count", len(collisions), "songs", collisions[:min(len(collisions), songNameCollisionsShown)])
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"effectiveMobile/internal/entities"
+	projectError "effectiveMobile/internal/errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// sortColumn is a sortable song field: the SQL expression it is ordered by
+// and the same value computed from a loaded song, which keyset cursors
+// store. Nullable columns are coalesced so that keyset comparisons never
+// meet NULL; the expressions match the indexes of migrations 0009 and 0010.
+type sortColumn struct {
+	expression string
+	value      func(song entities.Song) string
+	// parse converts a cursor value back for non-text columns
+	parse func(value string) (interface{}, error)
+}
+
+// sortColumns is the allow-list of sort fields; nothing else reaches ORDER BY.
+var sortColumns = map[string]sortColumn{
+	"group": {
+		expression: "coalesce(group_name, '')",
+		value:      func(song entities.Song) string { return song.GroupName },
+	},
+	"song": {
+		expression: "coalesce(song, '')",
+		value:      func(song entities.Song) string { return song.Song },
+	},
+	"releaseDate": {
+		expression: "coalesce(release_date, date '0001-01-01')",
+		value: func(song entities.Song) string {
+			if song.ReleaseDate.IsZero() {
+				return "0001-01-01"
+			}
+			return song.ReleaseDate.Format(time.DateOnly)
+		},
+		parse: func(value string) (interface{}, error) {
+			return time.Parse(time.DateOnly, value)
+		},
+	},
+	"id": {
+		expression: "id",
+		value:      func(song entities.Song) string { return strconv.FormatUint(uint64(song.ID), 10) },
+		parse: func(value string) (interface{}, error) {
+			return strconv.ParseUint(value, 10, 64)
+		},
+	},
+}
+
+// IsSortable tells whether songs can be sorted by the json field name.
+func IsSortable(field string) bool {
+	_, ok := sortColumns[field]
+	return ok
+}
+
+// SortValues returns the sort key values of song for a keyset cursor.
+func SortValues(song entities.Song, sort []entities.SortKey) []string {
+	values := make([]string, 0, len(sort))
+	for _, key := range withTieBreaker(sort) {
+		values = append(values, sortColumns[key.Field].value(song))
+	}
+	return values
+}
+
+// withTieBreaker appends id unless sort already has it, so the order is total.
+func withTieBreaker(sort []entities.SortKey) []entities.SortKey {
+	for _, key := range sort {
+		if key.Field == "id" {
+			return sort
+		}
+	}
+	return append(sort[:len(sort):len(sort)], entities.SortKey{Field: "id"})
+}
+
+// orderSongs orders query by sort, reversed when backward.
+func orderSongs(query *gorm.DB, sort []entities.SortKey, backward bool) *gorm.DB {
+	for _, key := range withTieBreaker(sort) {
+		direction := "ASC"
+		if key.Desc != backward {
+			direction = "DESC"
+		}
+		query = query.Order(sortColumns[key.Field].expression + " " + direction)
+	}
+	return query
+}
+
+// seekSongs keeps the rows after the cursor position in the sort order, or
+// before it when backward: (a > v1) OR (a = v1 AND b > v2) OR ...
+// values come from a client-supplied cursor, so they are checked to fit sort.
+func seekSongs(query *gorm.DB, sort []entities.SortKey, values []string, backward bool) (*gorm.DB, error) {
+	keys := withTieBreaker(sort)
+	if len(values) != len(keys) {
+		return nil, projectError.ErrIncorrectRequest
+	}
+	typed := make([]interface{}, len(values))
+	for i, key := range keys {
+		typed[i] = values[i]
+		if parse := sortColumns[key.Field].parse; parse != nil {
+			value, err := parse(values[i])
+			if err != nil {
+				return nil, projectError.ErrIncorrectRequest
+			}
+			typed[i] = value
+		}
+	}
+
+	conditions := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys)*(len(keys)+1)/2)
+	for i, key := range keys {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, sortColumns[keys[j].Field].expression+" = ?")
+			args = append(args, typed[j])
+		}
+		operator := ">"
+		if key.Desc != backward {
+			operator = "<"
+		}
+		parts = append(parts, sortColumns[key.Field].expression+" "+operator+" ?")
+		args = append(args, typed[i])
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...), nil
+}
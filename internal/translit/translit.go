@@ -0,0 +1,80 @@
+// Package translit builds script-independent search keys, so that e.g.
+// "Кино" and "Kino" or "Ария" and "Aria" compare equal.
+package translit
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// cyrillic maps Cyrillic letters to a plain Latin transliteration. й and ё
+// are not listed: they are reduced to и and е when diacritics are stripped.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh",
+	'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya",
+	// ukrainian and belarusian
+	'і': "i", 'ї': "i", 'є': "e", 'ґ': "g", 'ў': "u",
+}
+
+// latin folds spellings that differ only by transliteration convention into
+// one form. Replacements are applied in order, so longer patterns go first.
+var latin = strings.NewReplacer(
+	"shch", "sch",
+	"kh", "h",
+	"ph", "f",
+	"ck", "k",
+	"tz", "ts",
+	"ch", "ch",
+	"x", "ks",
+	"q", "k",
+	"w", "v",
+	"j", "i",
+	"y", "i",
+	"c", "k",
+)
+
+// Key returns the search key of s: lower case, without diacritics, spaces
+// and punctuation, with Cyrillic transliterated and common Latin spelling
+// variants folded together. Keys are meant for equality and substring
+// matching, not for display.
+func Key(s string) string {
+	var latinized strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining marks left by NFD: ё → е, й → и, é → e
+		case unicode.IsDigit(r):
+			latinized.WriteRune(r)
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			latinized.WriteRune(r)
+		default:
+			if latin, ok := cyrillic[r]; ok {
+				latinized.WriteString(latin)
+			} else if unicode.IsLetter(r) {
+				latinized.WriteRune(r)
+			}
+		}
+	}
+	return squeeze(latin.Replace(latinized.String()))
+}
+
+// squeeze collapses runs of the same letter, so doubled letters ("Металлика",
+// "Metallica") and й/я spelled as "ii" ("Ария" → "ariia") match their
+// single-letter spellings. Digits are kept: "Би-2" is not "Би-22".
+func squeeze(s string) string {
+	var result strings.Builder
+	var previous rune
+	for i, r := range s {
+		if i > 0 && r == previous && unicode.IsLetter(r) {
+			continue
+		}
+		result.WriteRune(r)
+		previous = r
+	}
+	return result.String()
+}
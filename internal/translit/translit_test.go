@@ -0,0 +1,73 @@
+package translit
+
+import "testing"
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"Кино", "kino"},
+		{"Kino", "kino"},
+		{"КИНО", "kino"},
+		{"Ария", "aria"},
+		{"Aria", "aria"},
+		{"Ariya", "aria"},
+		{"Металлика", "metalika"},
+		{"Metallica", "metalika"},
+		{"Мумий Тролль", "mumitrol"},
+		{"Mumiy Troll", "mumitrol"},
+		{"Mumij Troll'", "mumitrol"},
+		{"Цой", "tsoi"},
+		{"Tsoy", "tsoi"},
+		{"Tzoi", "tsoi"},
+		{"Чайф", "chaif"},
+		{"Chaif", "chaif"},
+		{"Щука", "schuka"},
+		{"Shchuka", "schuka"},
+		{"Хабиб", "habib"},
+		{"Khabib", "habib"},
+		{"Жуки", "zhuki"},
+		{"Zhuki", "zhuki"},
+		{"Ёлка", "elka"},
+		{"Елка", "elka"},
+		{"Би-2", "bi2"},
+		{"Bi 2", "bi2"},
+		{"1999", "1999"},
+		{"Би-22", "bi22"},
+		{"Beyoncé", "beionke"},
+		{"Beyonce", "beionke"},
+		{"Їжак", "izhak"},
+		{"  Кино!  ", "kino"},
+	}
+	for _, test := range tests {
+		if got := Key(test.input); got != test.want {
+			t.Errorf("Key(%q) = %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestKeyDistinct(t *testing.T) {
+	// names that differ by more than spelling must keep differing
+	tests := [][2]string{
+		{"Кино", "Кони"},
+		{"Кино", "Кина"},
+		{"Ария", "Арина"},
+		{"Жара", "Зара"},
+		{"Шум", "Сум"},
+		{"Чайф", "Чайка"},
+		{"Щука", "Сука"},
+		{"Би-2", "Би-3"},
+		{"Би-2", "Би-22"},
+		{"Сплин", "Спин"},
+		{"Kino", "Kin"},
+		{"Metallica", "Metal"},
+		{"Мама", "Папа"},
+	}
+	for _, test := range tests {
+		if a, b := Key(test[0]), Key(test[1]); a == b {
+			t.Errorf("Key(%q) and Key(%q) are both %q", test[0], test[1], a)
+		}
+	}
+}